@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -138,9 +140,8 @@ type OrderStrategy struct {
 	OrderActivityCollection  []OrderActivity      `json:"orderActivityCollection"`
 	ReplacingOrderCollection []struct {
 	} `json:"replacingOrderCollection"`
-	ChildOrderStrategies []struct {
-	} `json:"childOrderStrategies"`
-	StatusDescription string `json:"statusDescription"`
+	ChildOrderStrategies []OrderStrategy `json:"childOrderStrategies"`
+	StatusDescription    string          `json:"statusDescription"`
 }
 
 type Balance struct {
@@ -258,6 +259,23 @@ type OrderParams struct {
 	Status     string
 }
 
+func (p *OrderParams) values() url.Values {
+	q := url.Values{}
+	if p.MaxResults > 0 {
+		q.Set("maxResults", strconv.Itoa(p.MaxResults))
+	}
+	if !p.From.IsZero() {
+		q.Set("fromEnteredTime", p.From.Format("2006-01-02"))
+	}
+	if !p.To.IsZero() {
+		q.Set("toEnteredTime", p.To.Format("2006-01-02"))
+	}
+	if p.Status != "" {
+		q.Set("status", p.Status)
+	}
+	return q
+}
+
 func (i *Instrument) UnmarshalJSON(bs []byte) (err error) {
 	instrument := _Instrument{}
 
@@ -444,6 +462,11 @@ func (s *AccountsService) GetOrderByPath(ctx context.Context, accountID string,
 
 func (s *AccountsService) GetOrderByQuery(ctx context.Context, accountID string, orderParams *OrderParams) (*Orders, *Response, error) {
 	u := fmt.Sprintf("accounts/%s/orders", accountID)
+	if orderParams != nil {
+		if q := orderParams.values(); len(q) > 0 {
+			u = fmt.Sprintf("%s?%s", u, q.Encode())
+		}
+	}
 	req, err := s.client.NewRequest("GET", u, nil)
 	if err != nil {
 		return nil, nil, err
@@ -0,0 +1,4 @@
+// Package analytics prices options and computes their Greeks under the Black-Scholes model.
+// It is built to work directly with option chains returned by the tdameritrade package,
+// so values can be recomputed under the caller's own assumptions instead of TD Ameritrade's.
+package analytics
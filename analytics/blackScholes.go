@@ -0,0 +1,143 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+)
+
+// OptionType is either a call or a put.
+type OptionType int
+
+const (
+	Call OptionType = iota
+	Put
+)
+
+// ParseOptionType converts TD Ameritrade's "CALL" and "PUT" into an OptionType.
+func ParseOptionType(putCall string) (OptionType, error) {
+	switch putCall {
+	case "CALL", "C":
+		return Call, nil
+	case "PUT", "P":
+		return Put, nil
+	default:
+		return Call, fmt.Errorf("invalid putCall %q", putCall)
+	}
+}
+
+func (t OptionType) String() string {
+	if t == Put {
+		return "PUT"
+	}
+	return "CALL"
+}
+
+// Params are the inputs to the Black-Scholes model.
+// Rates, yields and volatility are annualized decimals (0.05 is 5%), not percentages.
+type Params struct {
+	Type       OptionType
+	Spot       float64 // price of the underlying
+	Strike     float64
+	Years      float64 // time to expiration in years
+	Rate       float64 // continuously compounded risk-free rate
+	Dividend   float64 // continuous dividend yield
+	Volatility float64
+}
+
+// Greeks are the sensitivities of an option's price.
+// They follow TD Ameritrade's conventions: Theta is per calendar day, Vega is per volatility point
+// and Rho is per percentage point of interest rate.
+type Greeks struct {
+	Delta float64
+	Gamma float64
+	Theta float64
+	Vega  float64
+	Rho   float64
+}
+
+// expired reports whether the model has degenerated to intrinsic value.
+func (p Params) expired() bool {
+	return p.Years <= 0 || p.Volatility <= 0
+}
+
+// intrinsic is the option's value without volatility: the payoff on the forward, discounted
+// to today. It is the plain intrinsic value once the option has expired.
+func (p Params) intrinsic() float64 {
+	spot := p.Spot
+	strike := p.Strike
+	if p.Years > 0 {
+		spot *= math.Exp(-p.Dividend * p.Years)
+		strike *= math.Exp(-p.Rate * p.Years)
+	}
+	if p.Type == Put {
+		return math.Max(strike-spot, 0)
+	}
+	return math.Max(spot-strike, 0)
+}
+
+func (p Params) d1d2() (float64, float64) {
+	sqrtT := math.Sqrt(p.Years)
+	d1 := (math.Log(p.Spot/p.Strike) + (p.Rate-p.Dividend+p.Volatility*p.Volatility/2)*p.Years) / (p.Volatility * sqrtT)
+	return d1, d1 - p.Volatility*sqrtT
+}
+
+// Price returns the Black-Scholes value of a European option.
+// An expired option is worth its intrinsic value, and one with no volatility is worth
+// its discounted intrinsic value, max(S·e^(-qT) − K·e^(-rT), 0) for a call.
+func Price(p Params) float64 {
+	if p.expired() {
+		return p.intrinsic()
+	}
+	d1, d2 := p.d1d2()
+	spot := p.Spot * math.Exp(-p.Dividend*p.Years)
+	strike := p.Strike * math.Exp(-p.Rate*p.Years)
+	if p.Type == Put {
+		return strike*normCDF(-d2) - spot*normCDF(-d1)
+	}
+	return spot*normCDF(d1) - strike*normCDF(d2)
+}
+
+// ComputeGreeks returns the Black-Scholes Greeks of a European option.
+func ComputeGreeks(p Params) Greeks {
+	if p.expired() {
+		var delta float64
+		if p.intrinsic() > 0 {
+			delta = 1
+			if p.Type == Put {
+				delta = -1
+			}
+		}
+		return Greeks{Delta: delta}
+	}
+
+	d1, d2 := p.d1d2()
+	sqrtT := math.Sqrt(p.Years)
+	qDisc := math.Exp(-p.Dividend * p.Years)
+	rDisc := math.Exp(-p.Rate * p.Years)
+	pdf := normPDF(d1)
+
+	g := Greeks{
+		Gamma: qDisc * pdf / (p.Spot * p.Volatility * sqrtT),
+		Vega:  p.Spot * qDisc * pdf * sqrtT / 100,
+	}
+	decay := -p.Spot * qDisc * pdf * p.Volatility / (2 * sqrtT)
+	if p.Type == Put {
+		g.Delta = qDisc * (normCDF(d1) - 1)
+		g.Theta = decay + p.Rate*p.Strike*rDisc*normCDF(-d2) - p.Dividend*p.Spot*qDisc*normCDF(-d1)
+		g.Rho = -p.Strike * p.Years * rDisc * normCDF(-d2) / 100
+	} else {
+		g.Delta = qDisc * normCDF(d1)
+		g.Theta = decay - p.Rate*p.Strike*rDisc*normCDF(d2) + p.Dividend*p.Spot*qDisc*normCDF(d1)
+		g.Rho = p.Strike * p.Years * rDisc * normCDF(d2) / 100
+	}
+	g.Theta /= 365
+	return g
+}
+
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+func normPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPrice(t *testing.T) {
+	p := Params{Type: Call, Spot: 100, Strike: 100, Years: 1, Rate: 0.05, Volatility: 0.2}
+	if got := Price(p); math.Abs(got-10.4506) > 1e-4 {
+		t.Errorf("call price = %v, want 10.4506", got)
+	}
+	p.Type = Put
+	if got := Price(p); math.Abs(got-5.5735) > 1e-4 {
+		t.Errorf("put price = %v, want 5.5735", got)
+	}
+
+	// without volatility the option is worth its discounted intrinsic value
+	p = Params{Type: Call, Spot: 100, Strike: 100, Years: 1, Rate: 0.05}
+	if got, want := Price(p), 100-100*math.Exp(-0.05); math.Abs(got-want) > 1e-9 {
+		t.Errorf("zero volatility call price = %v, want %v", got, want)
+	}
+	p = Params{Type: Put, Spot: 90, Strike: 100, Years: 1, Rate: 0.05}
+	if got, want := Price(p), 100*math.Exp(-0.05)-90; math.Abs(got-want) > 1e-9 {
+		t.Errorf("zero volatility put price = %v, want %v", got, want)
+	}
+	p.Years = 0
+	if got := Price(p); got != 10 {
+		t.Errorf("expired put price = %v, want 10", got)
+	}
+}
+
+func TestComputeGreeks(t *testing.T) {
+	p := Params{Type: Call, Spot: 100, Strike: 100, Years: 1, Rate: 0.05, Volatility: 0.2}
+	g := ComputeGreeks(p)
+	if math.Abs(g.Delta-0.6368) > 1e-4 {
+		t.Errorf("delta = %v, want 0.6368", g.Delta)
+	}
+	if math.Abs(g.Gamma-0.01876) > 1e-5 {
+		t.Errorf("gamma = %v, want 0.01876", g.Gamma)
+	}
+	if math.Abs(g.Vega-0.3752) > 1e-4 {
+		t.Errorf("vega = %v, want 0.3752", g.Vega)
+	}
+
+	p.Type = Put
+	pg := ComputeGreeks(p)
+	if math.Abs(g.Delta-pg.Delta-1) > 1e-9 {
+		t.Errorf("call delta - put delta = %v, want 1", g.Delta-pg.Delta)
+	}
+}
+
+func TestImpliedVolatility(t *testing.T) {
+	for _, vol := range []float64{0.05, 0.2, 0.8, 2} {
+		for _, strike := range []float64{50, 100, 150} {
+			p := Params{Type: Put, Spot: 100, Strike: strike, Years: 0.25, Rate: 0.01, Volatility: vol}
+			price := Price(p)
+			p.Volatility = 0
+			got, err := ImpliedVolatility(p, price)
+			if err != nil {
+				t.Fatalf("strike %v vol %v: %v", strike, vol, err)
+			}
+			if math.Abs(Price(Params{Type: Put, Spot: 100, Strike: strike, Years: 0.25, Rate: 0.01, Volatility: got})-price) > 1e-6 {
+				t.Errorf("strike %v vol %v: implied %v does not reprice", strike, vol, got)
+			}
+		}
+	}
+
+	p := Params{Type: Call, Spot: 100, Strike: 100, Years: 1}
+	if _, err := ImpliedVolatility(p, 150); err == nil {
+		t.Error("expected error for price above the spot")
+	}
+}
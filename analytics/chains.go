@@ -0,0 +1,72 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+// ChainParams builds model inputs for an option in a chain returned by ChainsService.GetChains.
+// TD Ameritrade reports Chains.InterestRate and ExpDateOption.Volatility as percentages, which are
+// converted to decimals here. Time to expiration is the time left until ExpDateOption.ExpirationDate
+// over 365 days, so an option expiring today still has hours left, and falls back to
+// ExpDateOption.DaysToExpiration when no expiration date was reported.
+func ChainParams(chains *tdameritrade.Chains, option *tdameritrade.ExpDateOption) (Params, error) {
+	optionType, err := ParseOptionType(option.PutCall)
+	if err != nil {
+		return Params{}, err
+	}
+	spot := chains.SpotPrice()
+	if spot <= 0 {
+		return Params{}, fmt.Errorf("chain for %s has no underlying price", chains.Symbol)
+	}
+	years := float64(option.DaysToExpiration) / 365
+	if option.ExpirationDate > 0 {
+		left := time.Until(tdameritrade.FromEpochMillis(int64(option.ExpirationDate)))
+		years = math.Max(left.Hours()/24/365, 0)
+	}
+	return Params{
+		Type:       optionType,
+		Spot:       spot,
+		Strike:     option.StrikePrice,
+		Years:      years,
+		Rate:       chains.InterestRate / 100,
+		Volatility: option.Volatility / 100,
+	}, nil
+}
+
+// MarketPrice returns the price used to solve for implied volatility: the option's mark,
+// or the midpoint of its bid and ask when TD Ameritrade did not report a mark.
+func MarketPrice(option *tdameritrade.ExpDateOption) float64 {
+	if option.Mark > 0 {
+		return option.Mark
+	}
+	if option.Bid > 0 && option.Ask > 0 {
+		return (option.Bid + option.Ask) / 2
+	}
+	return option.Last
+}
+
+// ChainImpliedVolatility solves for the implied volatility of an option in a chain from its market price.
+func ChainImpliedVolatility(chains *tdameritrade.Chains, option *tdameritrade.ExpDateOption) (float64, error) {
+	p, err := ChainParams(chains, option)
+	if err != nil {
+		return 0, err
+	}
+	return ImpliedVolatility(p, MarketPrice(option))
+}
+
+// ChainGreeks recomputes the Greeks of an option in a chain.
+// Volatility is implied from the option's market price rather than taken from TD Ameritrade.
+func ChainGreeks(chains *tdameritrade.Chains, option *tdameritrade.ExpDateOption) (Greeks, error) {
+	p, err := ChainParams(chains, option)
+	if err != nil {
+		return Greeks{}, err
+	}
+	if p.Volatility, err = ImpliedVolatility(p, MarketPrice(option)); err != nil {
+		return Greeks{}, err
+	}
+	return ComputeGreeks(p), nil
+}
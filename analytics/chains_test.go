@@ -0,0 +1,44 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+func TestChainParamsYears(t *testing.T) {
+	chains := &tdameritrade.Chains{Symbol: "SPY", UnderlyingPrice: 100, InterestRate: 5}
+
+	// a same-day option still has the hours until it expires
+	expiration := time.Now().Add(6 * time.Hour)
+	option := &tdameritrade.ExpDateOption{
+		PutCall:          "CALL",
+		StrikePrice:      100,
+		Volatility:       20,
+		DaysToExpiration: 0,
+		ExpirationDate:   int(tdameritrade.EpochMillis(expiration)),
+	}
+	p, err := ChainParams(chains, option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 6.0 / 24 / 365; math.Abs(p.Years-want) > 1e-6 {
+		t.Errorf("expected %v years, got %v", want, p.Years)
+	}
+	if p.Rate != 0.05 || p.Volatility != 0.2 {
+		t.Errorf("expected percentages converted to decimals, got %+v", p)
+	}
+
+	option.ExpirationDate = int(tdameritrade.EpochMillis(time.Now().Add(-time.Hour)))
+	if p, _ := ChainParams(chains, option); p.Years != 0 {
+		t.Errorf("expected an expired option to have no time left, got %v years", p.Years)
+	}
+
+	option.ExpirationDate = 0
+	option.DaysToExpiration = 73
+	if p, _ := ChainParams(chains, option); math.Abs(p.Years-0.2) > 1e-9 {
+		t.Errorf("expected days to expiration without an expiration date, got %v years", p.Years)
+	}
+}
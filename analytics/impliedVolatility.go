@@ -0,0 +1,61 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	ivTolerance     = 1e-8
+	ivMaxIterations = 100
+	ivLowerBound    = 1e-6
+	ivUpperBound    = 5.0
+)
+
+// ImpliedVolatility solves for the volatility at which the Black-Scholes price equals price.
+// p.Volatility is used as the starting guess when it is set. Newton's method is tried first
+// and bisection is used when it fails to converge, which happens for deep in or out of the money options.
+func ImpliedVolatility(p Params, price float64) (float64, error) {
+	if p.Years <= 0 {
+		return 0, fmt.Errorf("option has expired")
+	}
+	lower, upper := p, p
+	lower.Volatility, upper.Volatility = ivLowerBound, ivUpperBound
+	if price < Price(lower)-ivTolerance || price > Price(upper)+ivTolerance {
+		return 0, fmt.Errorf("price %v is outside the range the model can produce", price)
+	}
+
+	if p.Volatility <= 0 {
+		p.Volatility = 0.3
+	}
+	for i := 0; i < ivMaxIterations; i++ {
+		diff := Price(p) - price
+		if math.Abs(diff) < ivTolerance {
+			return p.Volatility, nil
+		}
+		vega := ComputeGreeks(p).Vega * 100
+		if vega < 1e-10 {
+			break
+		}
+		next := p.Volatility - diff/vega
+		if next <= ivLowerBound || next >= ivUpperBound || math.IsNaN(next) {
+			break
+		}
+		p.Volatility = next
+	}
+
+	lo, hi := ivLowerBound, ivUpperBound
+	for i := 0; i < 200; i++ {
+		p.Volatility = (lo + hi) / 2
+		diff := Price(p) - price
+		if math.Abs(diff) < ivTolerance || hi-lo < ivTolerance {
+			return p.Volatility, nil
+		}
+		if diff > 0 {
+			hi = p.Volatility
+		} else {
+			lo = p.Volatility
+		}
+	}
+	return p.Volatility, nil
+}
@@ -0,0 +1,271 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+// Leg is one instrument of a Strategy.
+type Leg struct {
+	// Option is the contract of an option leg, or nil for a stock leg.
+	Option *tdameritrade.OptionSymbol
+	// Quantity is positive for long legs and negative for short legs, in contracts or shares.
+	Quantity float64
+	// Multiplier is the number of shares per contract. Stock legs use 1.
+	Multiplier float64
+	// Volatility prices the leg before expiration. If zero, Strategy.Volatility is used.
+	Volatility float64
+}
+
+// Strategy is a set of legs on one underlying and what it cost to open them.
+type Strategy struct {
+	Underlying string
+	Legs       []Leg
+	// Cost is the total amount paid to open the strategy, negative for a credit.
+	Cost float64
+	// Rate and Volatility are the Black-Scholes inputs used before expiration, as decimals.
+	Rate       float64
+	Volatility float64
+}
+
+// PayoffSummary describes a strategy's P&L at its first expiration.
+type PayoffSummary struct {
+	MaxProfit float64
+	MaxLoss   float64
+	// UnlimitedProfit and UnlimitedLoss are set when P&L keeps growing as the underlying rises.
+	UnlimitedProfit bool
+	UnlimitedLoss   bool
+	Breakevens      []float64
+}
+
+// StrategyFromOrder builds a strategy from an order as it would be passed to PlaceOrder.
+// Cost is taken from the order's price for single-leg LIMIT orders and for NET_DEBIT, NET_CREDIT
+// and NET_ZERO orders. It is left zero for other order types and should be set by the caller.
+// A multi-leg LIMIT order is rejected, as its price does not say whether it is a debit or a credit.
+func StrategyFromOrder(order *tdameritrade.Order) (*Strategy, error) {
+	s := &Strategy{}
+	var perUnit float64
+	for _, l := range order.OrderLegCollection {
+		leg, underlying, err := legFromInstrument(l.Instrument)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.setUnderlying(underlying); err != nil {
+			return nil, err
+		}
+		switch {
+		case strings.HasPrefix(l.Instruction, "BUY"):
+			leg.Quantity = l.Quantity
+		case strings.HasPrefix(l.Instruction, "SELL"):
+			leg.Quantity = -l.Quantity
+		default:
+			return nil, fmt.Errorf("unsupported instruction %s", l.Instruction)
+		}
+		s.Legs = append(s.Legs, leg)
+		perUnit = leg.Quantity * leg.Multiplier
+	}
+	if len(s.Legs) == 0 {
+		return nil, fmt.Errorf("order has no legs")
+	}
+
+	switch order.OrderType {
+	case "LIMIT":
+		if len(s.Legs) > 1 {
+			return nil, fmt.Errorf("multi-leg LIMIT order has no debit or credit, use NET_DEBIT or NET_CREDIT")
+		}
+		s.Cost = order.Price * perUnit
+	case "NET_DEBIT", "NET_CREDIT", "NET_ZERO":
+		quantity := order.Quantity
+		if quantity == 0 {
+			quantity = math.Abs(s.Legs[0].Quantity)
+		}
+		s.Cost = order.Price * quantity * s.Legs[0].Multiplier
+		if order.OrderType == "NET_CREDIT" {
+			s.Cost = -s.Cost
+		}
+	}
+	return s, nil
+}
+
+// StrategyFromPositions builds a strategy from account positions on a single underlying,
+// using each position's average price as its cost.
+func StrategyFromPositions(positions []tdameritrade.Position) (*Strategy, error) {
+	s := &Strategy{}
+	for _, p := range positions {
+		leg, underlying, err := legFromInstrument(p.Instrument)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.setUnderlying(underlying); err != nil {
+			return nil, err
+		}
+		leg.Quantity = p.LongQuantity - p.ShortQuantity
+		s.Cost += p.AveragePrice * leg.Quantity * leg.Multiplier
+		s.Legs = append(s.Legs, leg)
+	}
+	if len(s.Legs) == 0 {
+		return nil, fmt.Errorf("no positions")
+	}
+	return s, nil
+}
+
+func legFromInstrument(i tdameritrade.Instrument) (Leg, string, error) {
+	switch data := i.Data.(type) {
+	case *tdameritrade.Equity:
+		return Leg{Multiplier: 1}, data.Symbol, nil
+	case *tdameritrade.OptionA:
+		o, err := data.OptionSymbol()
+		if err != nil {
+			return Leg{}, "", err
+		}
+		multiplier := data.OptionMultiplier
+		if multiplier == 0 {
+			multiplier = defaultOptionMultiplier
+		}
+		return Leg{Option: &o, Multiplier: multiplier}, o.Underlying, nil
+	default:
+		return Leg{}, "", fmt.Errorf("unsupported asset type %s", i.AssetType)
+	}
+}
+
+func (s *Strategy) setUnderlying(underlying string) error {
+	if s.Underlying != "" && s.Underlying != underlying {
+		return fmt.Errorf("legs have different underlyings %s and %s", s.Underlying, underlying)
+	}
+	s.Underlying = underlying
+	return nil
+}
+
+// Expiration returns the earliest expiration of the strategy's option legs.
+// It is the zero time if the strategy has no options.
+func (s *Strategy) Expiration() time.Time {
+	var first time.Time
+	for _, l := range s.Legs {
+		if l.Option != nil && (first.IsZero() || l.Option.Expiration.Before(first)) {
+			first = l.Option.Expiration
+		}
+	}
+	return first
+}
+
+// PL returns the strategy's profit or loss if the underlying is at spot on the given date.
+// Options that have not expired by then are valued with Black-Scholes.
+func (s *Strategy) PL(spot float64, at time.Time) float64 {
+	value := -s.Cost
+	for _, l := range s.Legs {
+		if l.Option == nil {
+			value += l.Quantity * spot
+			continue
+		}
+		optionType := Call
+		if l.Option.PutCall == "PUT" {
+			optionType = Put
+		}
+		vol := l.Volatility
+		if vol == 0 {
+			vol = s.Volatility
+		}
+		price := Price(Params{
+			Type:       optionType,
+			Spot:       spot,
+			Strike:     l.Option.Strike,
+			Years:      l.Option.Expiration.Sub(at).Hours() / 24 / 365,
+			Rate:       s.Rate,
+			Volatility: vol,
+		})
+		value += l.Quantity * l.Multiplier * price
+	}
+	return value
+}
+
+// Summary finds the strategy's maximum profit and loss and its breakevens at its first expiration,
+// evaluating prices from zero to high in the given number of steps.
+// high should comfortably exceed every strike.
+func (s *Strategy) Summary(high float64, steps int) PayoffSummary {
+	at := s.Expiration()
+	var summary PayoffSummary
+	prev := s.PL(0, at)
+	summary.MaxProfit, summary.MaxLoss = prev, prev
+	for i := 1; i <= steps; i++ {
+		spot := high * float64(i) / float64(steps)
+		pl := s.PL(spot, at)
+		summary.MaxProfit = math.Max(summary.MaxProfit, pl)
+		summary.MaxLoss = math.Min(summary.MaxLoss, pl)
+		switch {
+		case pl == 0 && prev != 0:
+			// only the first price of a run of zero P&L is a breakeven
+			summary.Breakevens = append(summary.Breakevens, spot)
+		case prev < 0 && pl > 0 || prev > 0 && pl < 0:
+			lo := high * float64(i-1) / float64(steps)
+			summary.Breakevens = append(summary.Breakevens, lo+(spot-lo)*prev/(prev-pl))
+		}
+		prev = pl
+	}
+
+	slope := s.PL(2*high, at) - s.PL(high, at)
+	summary.UnlimitedProfit = slope > 1e-9
+	summary.UnlimitedLoss = slope < -1e-9
+	if summary.UnlimitedProfit {
+		summary.MaxProfit = math.Inf(1)
+	}
+	if summary.UnlimitedLoss {
+		summary.MaxLoss = math.Inf(-1)
+	}
+	sort.Float64s(summary.Breakevens)
+	return summary
+}
+
+// Table returns P&L for each price (rows) on each date (columns).
+func (s *Strategy) Table(prices []float64, dates []time.Time) [][]float64 {
+	table := make([][]float64, len(prices))
+	for i, spot := range prices {
+		table[i] = make([]float64, len(dates))
+		for j, at := range dates {
+			table[i][j] = s.PL(spot, at)
+		}
+	}
+	return table
+}
+
+// WriteTable renders the P&L table as aligned text.
+func (s *Strategy) WriteTable(w io.Writer, prices []float64, dates []time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, row := range s.rows(prices, dates) {
+		fmt.Fprintln(tw, strings.Join(row, "\t")+"\t")
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes the P&L table as CSV with one column per date.
+func (s *Strategy) WriteCSV(w io.Writer, prices []float64, dates []time.Time) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(s.rows(prices, dates)); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func (s *Strategy) rows(prices []float64, dates []time.Time) [][]string {
+	header := []string{"price"}
+	for _, d := range dates {
+		header = append(header, d.Format("2006-01-02"))
+	}
+	rows := [][]string{header}
+	for i, pl := range s.Table(prices, dates) {
+		row := []string{strconv.FormatFloat(prices[i], 'f', 2, 64)}
+		for _, v := range pl {
+			row = append(row, strconv.FormatFloat(v, 'f', 2, 64))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,104 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+func optionLeg(symbol, instruction string) *tdameritrade.OrderLegCollection {
+	return &tdameritrade.OrderLegCollection{
+		Instruction: instruction,
+		Quantity:    1,
+		Instrument: tdameritrade.Instrument{
+			AssetType: "OPTION",
+			Data:      &tdameritrade.OptionA{Symbol: symbol},
+		},
+	}
+}
+
+func TestStrategyFromOrderVerticalSpread(t *testing.T) {
+	s, err := StrategyFromOrder(&tdameritrade.Order{
+		OrderType: "NET_DEBIT",
+		Price:     2,
+		Quantity:  1,
+		OrderLegCollection: []*tdameritrade.OrderLegCollection{
+			optionLeg("AAPL_011521C130", "BUY_TO_OPEN"),
+			optionLeg("AAPL_011521C135", "SELL_TO_OPEN"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Underlying != "AAPL" || s.Cost != 200 {
+		t.Fatalf("unexpected strategy %+v", s)
+	}
+
+	summary := s.Summary(300, 3000)
+	if math.Abs(summary.MaxProfit-300) > 1e-6 || math.Abs(summary.MaxLoss+200) > 1e-6 {
+		t.Errorf("expected max profit 300 and max loss -200, got %+v", summary)
+	}
+	if summary.UnlimitedProfit || summary.UnlimitedLoss {
+		t.Errorf("expected bounded payoff, got %+v", summary)
+	}
+	if len(summary.Breakevens) != 1 || math.Abs(summary.Breakevens[0]-132) > 1e-6 {
+		t.Errorf("expected breakeven at 132, got %v", summary.Breakevens)
+	}
+}
+
+func TestStrategyFromPositionsCoveredCall(t *testing.T) {
+	s, err := StrategyFromPositions([]tdameritrade.Position{
+		{LongQuantity: 100, AveragePrice: 120, Instrument: tdameritrade.Instrument{AssetType: "EQUITY", Data: &tdameritrade.Equity{Symbol: "AAPL"}}},
+		{ShortQuantity: 1, AveragePrice: 3, Instrument: tdameritrade.Instrument{AssetType: "OPTION", Data: &tdameritrade.OptionA{Symbol: "AAPL_011521C130", OptionMultiplier: 100}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := s.Summary(300, 3000)
+	if math.Abs(summary.MaxProfit-1300) > 1e-6 || math.Abs(summary.MaxLoss+11700) > 1e-6 {
+		t.Errorf("expected max profit 1300 and max loss -11700, got %+v", summary)
+	}
+	if len(summary.Breakevens) != 1 || math.Abs(summary.Breakevens[0]-117) > 1e-6 {
+		t.Errorf("expected breakeven at 117, got %v", summary.Breakevens)
+	}
+}
+
+func TestSummaryZeroRun(t *testing.T) {
+	// a market order leaves Cost zero, so P&L is zero for every price up to the strike
+	s, err := StrategyFromOrder(&tdameritrade.Order{
+		OrderType:          "MARKET",
+		OrderLegCollection: []*tdameritrade.OrderLegCollection{optionLeg("AAPL_011521C130", "BUY_TO_OPEN")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := s.Summary(300, 3000)
+	for _, b := range summary.Breakevens {
+		if math.IsNaN(b) {
+			t.Fatalf("expected no NaN breakevens, got %v", summary.Breakevens)
+		}
+	}
+
+	// a short call for a 5 credit reaches zero exactly on a price step, which is counted once
+	s.Legs[0].Quantity = -1
+	s.Cost = -500
+	summary = s.Summary(300, 3000)
+	if len(summary.Breakevens) != 1 || math.Abs(summary.Breakevens[0]-135) > 1e-6 {
+		t.Errorf("expected one breakeven at 135, got %v", summary.Breakevens)
+	}
+}
+
+func TestStrategyFromOrderMultiLegLimit(t *testing.T) {
+	_, err := StrategyFromOrder(&tdameritrade.Order{
+		OrderType: "LIMIT",
+		Price:     2,
+		OrderLegCollection: []*tdameritrade.OrderLegCollection{
+			optionLeg("AAPL_011521C130", "BUY_TO_OPEN"),
+			optionLeg("AAPL_011521C135", "SELL_TO_OPEN"),
+		},
+	})
+	if err == nil {
+		t.Error("expected a multi-leg LIMIT order to be rejected")
+	}
+}
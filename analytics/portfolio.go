@@ -0,0 +1,206 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+const defaultOptionMultiplier = 100
+
+// MarketData supplies the prices and Greeks AggregateRisk needs to value positions.
+type MarketData interface {
+	// OptionGreeks returns the per-contract Greeks of an option symbol.
+	OptionGreeks(symbol string) (Greeks, error)
+	// UnderlyingPrice returns the last price of an underlying symbol.
+	UnderlyingPrice(symbol string) (float64, error)
+}
+
+// ChainData is MarketData backed by option chains and, optionally, quotes for symbols without a chain.
+type ChainData struct {
+	// Recompute solves Greeks from each contract's market price with ChainGreeks
+	// instead of using the values reported by TD Ameritrade.
+	Recompute bool
+
+	options map[string]*tdameritrade.ExpDateOption
+	chains  map[string]*tdameritrade.Chains
+	prices  map[string]float64
+}
+
+// NewChainData indexes the contracts of the given chains by symbol.
+func NewChainData(chains ...*tdameritrade.Chains) (*ChainData, error) {
+	d := &ChainData{
+		options: map[string]*tdameritrade.ExpDateOption{},
+		chains:  map[string]*tdameritrade.Chains{},
+		prices:  map[string]float64{},
+	}
+	for _, c := range chains {
+		entries, err := c.Flatten()
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			d.options[e.Option.Symbol] = e.Option
+			d.chains[e.Option.Symbol] = c
+		}
+		if price := c.SpotPrice(); price != 0 {
+			d.prices[c.Symbol] = price
+		}
+	}
+	return d, nil
+}
+
+// AddQuotes adds underlying prices for symbols held directly, such as stocks without options.
+func (d *ChainData) AddQuotes(quotes tdameritrade.Quotes) {
+	for symbol, q := range quotes {
+		if q.Mark != 0 {
+			d.prices[symbol] = q.Mark
+		} else {
+			d.prices[symbol] = q.LastPrice
+		}
+	}
+}
+
+// OptionGreeks implements MarketData.
+func (d *ChainData) OptionGreeks(symbol string) (Greeks, error) {
+	o, ok := d.options[symbol]
+	if !ok {
+		return Greeks{}, fmt.Errorf("no chain data for %s", symbol)
+	}
+	if d.Recompute {
+		return ChainGreeks(d.chains[symbol], o)
+	}
+	return Greeks{Delta: o.Delta, Gamma: o.Gamma, Theta: o.Theta, Vega: o.Vega, Rho: o.Rho}, nil
+}
+
+// UnderlyingPrice implements MarketData.
+func (d *ChainData) UnderlyingPrice(symbol string) (float64, error) {
+	price, ok := d.prices[symbol]
+	if !ok {
+		return 0, fmt.Errorf("no price for %s", symbol)
+	}
+	return price, nil
+}
+
+// Exposure is the aggregate risk of a set of positions.
+// Delta and BetaWeightedDelta are in shares, Gamma is the change in Delta for a one dollar move,
+// Theta is dollars per day and Vega is dollars per volatility point.
+type Exposure struct {
+	Underlying        string
+	Delta             float64
+	Gamma             float64
+	Theta             float64
+	Vega              float64
+	Rho               float64
+	BetaWeightedDelta float64
+	// DollarDelta is Delta multiplied by the underlying price.
+	DollarDelta float64
+}
+
+// RiskOptions configures beta weighting in AggregateRisk.
+type RiskOptions struct {
+	// Betas of each underlying to the benchmark. Underlyings without a beta use 1.
+	Betas map[string]float64
+	// BenchmarkPrice is the price of the benchmark, e.g. SPY. BetaWeightedDelta is
+	// expressed in shares of the benchmark. If zero, beta-weighted delta is not computed.
+	BenchmarkPrice float64
+}
+
+// RiskReport is the output of AggregateRisk.
+type RiskReport struct {
+	// Underlyings holds one exposure per underlying, ordered by symbol.
+	Underlyings []Exposure
+	Total       Exposure
+	// Skipped lists positions that could not be valued and why.
+	Skipped []string
+}
+
+// AggregateRisk nets the Greeks of every position by underlying. Stock positions count as one
+// delta per share. Option positions are scaled by quantity and OptionA.OptionMultiplier.
+// Positions that cannot be valued are reported in RiskReport.Skipped rather than failing the report.
+func AggregateRisk(positions []tdameritrade.Position, data MarketData, opts *RiskOptions) *RiskReport {
+	if opts == nil {
+		opts = &RiskOptions{}
+	}
+	report := &RiskReport{}
+	exposures := map[string]*Exposure{}
+
+	for _, p := range positions {
+		quantity := p.LongQuantity - p.ShortQuantity
+		var underlying string
+		var g Greeks
+		switch instrument := p.Instrument.Data.(type) {
+		case *tdameritrade.Equity:
+			underlying = instrument.Symbol
+			g = Greeks{Delta: quantity}
+		case *tdameritrade.OptionA:
+			underlying = instrument.UnderlyingSymbol
+			contract, err := data.OptionGreeks(instrument.Symbol)
+			if err != nil {
+				report.Skipped = append(report.Skipped, err.Error())
+				continue
+			}
+			multiplier := instrument.OptionMultiplier
+			if multiplier == 0 {
+				multiplier = defaultOptionMultiplier
+			}
+			scale := quantity * multiplier
+			g = Greeks{
+				Delta: contract.Delta * scale,
+				Gamma: contract.Gamma * scale,
+				Theta: contract.Theta * scale,
+				Vega:  contract.Vega * scale,
+				Rho:   contract.Rho * scale,
+			}
+		default:
+			report.Skipped = append(report.Skipped, fmt.Sprintf("unsupported asset type %s", p.Instrument.AssetType))
+			continue
+		}
+
+		e, ok := exposures[underlying]
+		if !ok {
+			e = &Exposure{Underlying: underlying}
+			exposures[underlying] = e
+		}
+		e.Delta += g.Delta
+		e.Gamma += g.Gamma
+		e.Theta += g.Theta
+		e.Vega += g.Vega
+		e.Rho += g.Rho
+	}
+
+	// Visit underlyings in order so the report, including Skipped, is deterministic.
+	underlyings := make([]string, 0, len(exposures))
+	for underlying := range exposures {
+		underlyings = append(underlyings, underlying)
+	}
+	sort.Strings(underlyings)
+
+	for _, underlying := range underlyings {
+		e := exposures[underlying]
+		price, err := data.UnderlyingPrice(e.Underlying)
+		if err != nil {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("dollar and beta-weighted delta of %s: %v", e.Underlying, err))
+		} else {
+			e.DollarDelta = e.Delta * price
+			if opts.BenchmarkPrice != 0 {
+				beta, ok := opts.Betas[e.Underlying]
+				if !ok {
+					beta = 1
+				}
+				e.BetaWeightedDelta = e.DollarDelta * beta / opts.BenchmarkPrice
+			}
+		}
+
+		report.Underlyings = append(report.Underlyings, *e)
+		report.Total.Delta += e.Delta
+		report.Total.Gamma += e.Gamma
+		report.Total.Theta += e.Theta
+		report.Total.Vega += e.Vega
+		report.Total.Rho += e.Rho
+		report.Total.DollarDelta += e.DollarDelta
+		report.Total.BetaWeightedDelta += e.BetaWeightedDelta
+	}
+	return report
+}
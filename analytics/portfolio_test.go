@@ -0,0 +1,118 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+type fakeMarketData struct {
+	greeks map[string]Greeks
+	prices map[string]float64
+}
+
+func (f fakeMarketData) OptionGreeks(symbol string) (Greeks, error) {
+	g, ok := f.greeks[symbol]
+	if !ok {
+		return Greeks{}, fmt.Errorf("no greeks for %s", symbol)
+	}
+	return g, nil
+}
+
+func (f fakeMarketData) UnderlyingPrice(symbol string) (float64, error) {
+	price, ok := f.prices[symbol]
+	if !ok {
+		return 0, fmt.Errorf("no price for %s", symbol)
+	}
+	return price, nil
+}
+
+func equityPosition(symbol string, long, short float64) tdameritrade.Position {
+	return tdameritrade.Position{
+		LongQuantity:  long,
+		ShortQuantity: short,
+		Instrument:    tdameritrade.Instrument{AssetType: "EQUITY", Data: &tdameritrade.Equity{Symbol: symbol}},
+	}
+}
+
+func optionPosition(symbol, underlying string, multiplier, long, short float64) tdameritrade.Position {
+	return tdameritrade.Position{
+		LongQuantity:  long,
+		ShortQuantity: short,
+		Instrument: tdameritrade.Instrument{AssetType: "OPTION", Data: &tdameritrade.OptionA{
+			Symbol:           symbol,
+			UnderlyingSymbol: underlying,
+			OptionMultiplier: multiplier,
+		}},
+	}
+}
+
+func TestAggregateRisk(t *testing.T) {
+	data := fakeMarketData{
+		greeks: map[string]Greeks{
+			"AAPL_011521C150": {Delta: 0.5, Gamma: 0.02, Theta: -0.05, Vega: 0.1},
+			"MSFT_011521P200": {Delta: -0.4, Gamma: 0.01, Theta: -0.03, Vega: 0.2},
+			"NVDA_011521C500": {Delta: 0.3},
+		},
+		prices: map[string]float64{"AAPL": 150},
+	}
+	positions := []tdameritrade.Position{
+		equityPosition("AAPL", 300, 0),
+		// short two calls of the default 100 multiplier
+		optionPosition("AAPL_011521C150", "AAPL", 0, 0, 2),
+		// a long put on a 10 share deliverable
+		optionPosition("MSFT_011521P200", "MSFT", 10, 3, 0),
+		optionPosition("NVDA_011521C500", "NVDA", 100, 1, 0),
+		optionPosition("XYZ_011521C10", "XYZ", 100, 1, 0),
+	}
+	report := AggregateRisk(positions, data, &RiskOptions{
+		Betas:          map[string]float64{"AAPL": 1.2},
+		BenchmarkPrice: 400,
+	})
+
+	if len(report.Underlyings) != 3 {
+		t.Fatalf("expected 3 underlyings, got %+v", report.Underlyings)
+	}
+	aapl, msft := report.Underlyings[0], report.Underlyings[1]
+	if aapl.Underlying != "AAPL" || msft.Underlying != "MSFT" || report.Underlyings[2].Underlying != "NVDA" {
+		t.Fatalf("expected underlyings ordered by symbol, got %+v", report.Underlyings)
+	}
+
+	near := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v", name, want, got)
+		}
+	}
+	// 300 shares less 2 * 100 * 0.5
+	near("AAPL delta", aapl.Delta, 200)
+	near("AAPL gamma", aapl.Gamma, -4)
+	near("AAPL theta", aapl.Theta, 10)
+	near("AAPL vega", aapl.Vega, -20)
+	near("AAPL dollar delta", aapl.DollarDelta, 30000)
+	// 30000 * 1.2 / 400
+	near("AAPL beta-weighted delta", aapl.BetaWeightedDelta, 90)
+
+	// 3 * 10 * -0.4, without a price so no dollar or beta-weighted delta
+	near("MSFT delta", msft.Delta, -12)
+	near("MSFT vega", msft.Vega, 6)
+	near("MSFT dollar delta", msft.DollarDelta, 0)
+
+	near("total delta", report.Total.Delta, 200-12+30)
+	near("total beta-weighted delta", report.Total.BetaWeightedDelta, 90)
+
+	want := []string{
+		"no greeks for XYZ_011521C10",
+		"dollar and beta-weighted delta of MSFT: no price for MSFT",
+		"dollar and beta-weighted delta of NVDA: no price for NVDA",
+	}
+	for i := 0; i < 10; i++ {
+		report := AggregateRisk(positions, data, nil)
+		if !reflect.DeepEqual(report.Skipped, want) {
+			t.Fatalf("expected skipped %q, got %q", want, report.Skipped)
+		}
+	}
+}
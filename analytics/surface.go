@@ -0,0 +1,229 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+// SurfacePoint is the implied volatility of a single contract.
+type SurfacePoint struct {
+	Expiration       time.Time `json:"expiration"`
+	DaysToExpiration int       `json:"daysToExpiration"`
+	Strike           float64   `json:"strike"`
+	// Moneyness is the strike divided by the underlying price.
+	Moneyness float64 `json:"moneyness"`
+	PutCall   string  `json:"putCall"`
+	// Volatility is annualized and expressed as a decimal.
+	Volatility float64 `json:"volatility"`
+}
+
+// TermPoint is the at-the-money implied volatility of one expiration.
+type TermPoint struct {
+	Expiration       time.Time `json:"expiration"`
+	DaysToExpiration int       `json:"daysToExpiration"`
+	Volatility       float64   `json:"volatility"`
+}
+
+// SurfaceOptions controls which contracts go into a VolSurface.
+type SurfaceOptions struct {
+	// IncludeITM keeps in-the-money contracts. By default only out-of-the-money calls and puts are used,
+	// since they are more liquid and their quotes carry less early exercise premium.
+	IncludeITM bool
+	// Recompute solves implied volatility from each contract's market price
+	// instead of using TD Ameritrade's ExpDateOption.Volatility.
+	Recompute bool
+	// MinOpenInterest drops contracts with less open interest.
+	MinOpenInterest int
+}
+
+// VolSurface maps expiration and moneyness to implied volatility.
+type VolSurface struct {
+	Symbol          string         `json:"symbol"`
+	UnderlyingPrice float64        `json:"underlyingPrice"`
+	Points          []SurfacePoint `json:"points"`
+}
+
+// BuildSurface builds a volatility surface from an option chain. Expired contracts
+// and contracts without a usable volatility are skipped.
+func BuildSurface(chains *tdameritrade.Chains, opts *SurfaceOptions) (*VolSurface, error) {
+	if opts == nil {
+		opts = &SurfaceOptions{}
+	}
+	spot := chains.SpotPrice()
+	if spot <= 0 {
+		return nil, fmt.Errorf("chain for %s has no underlying price", chains.Symbol)
+	}
+
+	entries, err := chains.Flatten()
+	if err != nil {
+		return nil, err
+	}
+
+	surface := &VolSurface{Symbol: chains.Symbol, UnderlyingPrice: spot}
+	for _, e := range entries {
+		o := e.Option
+		if o.DaysToExpiration <= 0 || o.OpenInterest < opts.MinOpenInterest {
+			continue
+		}
+		if !opts.IncludeITM && (o.PutCall == "CALL" && o.StrikePrice < spot || o.PutCall == "PUT" && o.StrikePrice > spot) {
+			continue
+		}
+
+		vol := o.Volatility / 100
+		if opts.Recompute {
+			if vol, err = ChainImpliedVolatility(chains, o); err != nil {
+				continue
+			}
+		}
+		if vol <= 0 || math.IsNaN(vol) {
+			continue
+		}
+
+		surface.Points = append(surface.Points, SurfacePoint{
+			Expiration:       e.Expiration.Date,
+			DaysToExpiration: o.DaysToExpiration,
+			Strike:           o.StrikePrice,
+			Moneyness:        o.StrikePrice / spot,
+			PutCall:          o.PutCall,
+			Volatility:       vol,
+		})
+	}
+	if len(surface.Points) == 0 {
+		return nil, fmt.Errorf("chain for %s has no usable volatilities", chains.Symbol)
+	}
+	return surface, nil
+}
+
+// Expirations lists the days to expiration present in the surface, nearest first.
+func (s *VolSurface) Expirations() []int {
+	seen := map[int]bool{}
+	var days []int
+	for _, p := range s.Points {
+		if !seen[p.DaysToExpiration] {
+			seen[p.DaysToExpiration] = true
+			days = append(days, p.DaysToExpiration)
+		}
+	}
+	sort.Ints(days)
+	return days
+}
+
+// Skew returns the points of one expiration ordered by strike, with the call before the put
+// when both are present at a strike.
+func (s *VolSurface) Skew(daysToExpiration int) []SurfacePoint {
+	var skew []SurfacePoint
+	for _, p := range s.Points {
+		if p.DaysToExpiration == daysToExpiration {
+			skew = append(skew, p)
+		}
+	}
+	sort.SliceStable(skew, func(i, j int) bool {
+		if skew[i].Strike != skew[j].Strike {
+			return skew[i].Strike < skew[j].Strike
+		}
+		return skew[i].PutCall < skew[j].PutCall
+	})
+	return skew
+}
+
+// TermStructure returns the at-the-money volatility of each expiration, nearest first.
+func (s *VolSurface) TermStructure() []TermPoint {
+	var term []TermPoint
+	for _, days := range s.Expirations() {
+		skew := s.Skew(days)
+		term = append(term, TermPoint{
+			Expiration:       skew[0].Expiration,
+			DaysToExpiration: days,
+			Volatility:       interpolateSkew(skew, 1),
+		})
+	}
+	return term
+}
+
+// Volatility interpolates the surface at a number of days to expiration and a moneyness.
+// Within an expiration it interpolates linearly in moneyness. Between expirations it interpolates
+// linearly in total variance, which keeps forward volatility non-negative. Values outside the
+// surface are held flat at the nearest edge. An empty surface returns NaN.
+func (s *VolSurface) Volatility(daysToExpiration, moneyness float64) float64 {
+	expirations := s.Expirations()
+	if len(expirations) == 0 {
+		return math.NaN()
+	}
+	i := sort.Search(len(expirations), func(i int) bool {
+		return float64(expirations[i]) >= daysToExpiration
+	})
+	switch {
+	case i == 0:
+		return interpolateSkew(s.Skew(expirations[0]), moneyness)
+	case i == len(expirations):
+		return interpolateSkew(s.Skew(expirations[i-1]), moneyness)
+	}
+
+	t0, t1 := float64(expirations[i-1]), float64(expirations[i])
+	v0 := interpolateSkew(s.Skew(expirations[i-1]), moneyness)
+	v1 := interpolateSkew(s.Skew(expirations[i]), moneyness)
+	w := (daysToExpiration - t0) / (t1 - t0)
+	variance := (1-w)*v0*v0*t0 + w*v1*v1*t1
+	return math.Sqrt(variance / daysToExpiration)
+}
+
+// interpolateSkew interpolates linearly in moneyness across points sorted by strike.
+// Points sharing a moneyness, such as a call and a put at the same strike, are averaged.
+func interpolateSkew(skew []SurfacePoint, moneyness float64) float64 {
+	var xs, vols []float64
+	for i := 0; i < len(skew); {
+		j, sum := i, 0.0
+		for ; j < len(skew) && skew[j].Moneyness == skew[i].Moneyness; j++ {
+			sum += skew[j].Volatility
+		}
+		xs = append(xs, skew[i].Moneyness)
+		vols = append(vols, sum/float64(j-i))
+		i = j
+	}
+
+	i := sort.SearchFloat64s(xs, moneyness)
+	switch {
+	case i == 0:
+		return vols[0]
+	case i == len(xs):
+		return vols[i-1]
+	}
+	w := (moneyness - xs[i-1]) / (xs[i] - xs[i-1])
+	return vols[i-1] + w*(vols[i]-vols[i-1])
+}
+
+// WriteCSV writes one row per point with a header row.
+func (s *VolSurface) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"expiration", "daysToExpiration", "strike", "moneyness", "putCall", "volatility"}); err != nil {
+		return err
+	}
+	for _, p := range s.Points {
+		err := cw.Write([]string{
+			p.Expiration.Format("2006-01-02"),
+			strconv.Itoa(p.DaysToExpiration),
+			strconv.FormatFloat(p.Strike, 'f', -1, 64),
+			strconv.FormatFloat(p.Moneyness, 'f', 6, 64),
+			p.PutCall,
+			strconv.FormatFloat(p.Volatility, 'f', 6, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the surface as a single JSON object.
+func (s *VolSurface) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
@@ -0,0 +1,140 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+func surfaceOption(putCall string, strike float64, days int, vol float64) []tdameritrade.ExpDateOption {
+	return []tdameritrade.ExpDateOption{{PutCall: putCall, StrikePrice: strike, DaysToExpiration: days, Volatility: vol, OpenInterest: 10}}
+}
+
+func testSurfaceChain() *tdameritrade.Chains {
+	return &tdameritrade.Chains{
+		Symbol:          "SPY",
+		UnderlyingPrice: 100,
+		CallExpDateMap: tdameritrade.ExpDateMap{
+			"2021-01-15:0": {"100.0": surfaceOption("CALL", 100, 0, 25)},
+			"2021-02-14:30": {
+				"90.0":  surfaceOption("CALL", 90, 30, 35),
+				"100.0": surfaceOption("CALL", 100, 30, 20),
+				"110.0": surfaceOption("CALL", 110, 30, 18),
+			},
+			"2021-04-15:90": {
+				"100.0": surfaceOption("CALL", 100, 90, 22),
+				"110.0": surfaceOption("CALL", 110, 90, 20),
+			},
+		},
+		PutExpDateMap: tdameritrade.ExpDateMap{
+			"2021-02-14:30": {
+				"90.0":  surfaceOption("PUT", 90, 30, 30),
+				"100.0": surfaceOption("PUT", 100, 30, 20),
+			},
+			"2021-04-15:90": {
+				"90.0":  surfaceOption("PUT", 90, 90, 28),
+				"100.0": surfaceOption("PUT", 100, 90, 22),
+			},
+		},
+	}
+}
+
+func TestBuildSurface(t *testing.T) {
+	surface, err := BuildSurface(testSurfaceChain(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the expired contract and the in-the-money call are left out
+	if len(surface.Points) != 8 {
+		t.Fatalf("expected 8 points, got %d", len(surface.Points))
+	}
+	if days := surface.Expirations(); len(days) != 2 || days[0] != 30 || days[1] != 90 {
+		t.Errorf("unexpected expirations %v", days)
+	}
+
+	term := surface.TermStructure()
+	if len(term) != 2 || math.Abs(term[0].Volatility-0.20) > 1e-9 || math.Abs(term[1].Volatility-0.22) > 1e-9 {
+		t.Errorf("unexpected term structure %+v", term)
+	}
+
+	// between expirations the total variance is interpolated
+	want := math.Sqrt((0.5*0.04*30 + 0.5*0.0484*90) / 60)
+	if got := surface.Volatility(60, 1); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Volatility(60, 1) = %v, want %v", got, want)
+	}
+	// before the first expiration the nearest skew is used, interpolated in moneyness
+	if got := surface.Volatility(10, 1.05); math.Abs(got-0.19) > 1e-9 {
+		t.Errorf("Volatility(10, 1.05) = %v, want 0.19", got)
+	}
+
+	withITM, err := BuildSurface(testSurfaceChain(), &SurfaceOptions{IncludeITM: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(withITM.Points) != 9 {
+		t.Errorf("expected the in-the-money call to be included, got %d points", len(withITM.Points))
+	}
+
+	if v := (&VolSurface{}).Volatility(30, 1); !math.IsNaN(v) {
+		t.Errorf("expected NaN from an empty surface, got %v", v)
+	}
+}
+
+func TestSkewAtTheMoney(t *testing.T) {
+	chain := testSurfaceChain()
+	chain.PutExpDateMap["2021-02-14:30"]["100.0"] = surfaceOption("PUT", 100, 30, 24)
+	surface, err := BuildSurface(chain, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		// reverse the points so the sort can't rely on their input order
+		for l, r := 0, len(surface.Points)-1; l < r; l, r = l+1, r-1 {
+			surface.Points[l], surface.Points[r] = surface.Points[r], surface.Points[l]
+		}
+		skew := surface.Skew(30)
+		if len(skew) != 4 || skew[1].PutCall != "CALL" || skew[2].PutCall != "PUT" {
+			t.Fatalf("expected the call before the put at the same strike, got %+v", skew)
+		}
+		// the call and put at the money are averaged
+		if got := surface.Volatility(30, 1); math.Abs(got-0.22) > 1e-9 {
+			t.Fatalf("Volatility(30, 1) = %v, want 0.22", got)
+		}
+		if got := surface.Volatility(30, 0.95); math.Abs(got-0.26) > 1e-9 {
+			t.Fatalf("Volatility(30, 0.95) = %v, want 0.26", got)
+		}
+	}
+}
+
+func TestVolSurfaceWrite(t *testing.T) {
+	surface, err := BuildSurface(testSurfaceChain(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := surface.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 9 || lines[0] != "expiration,daysToExpiration,strike,moneyness,putCall,volatility" {
+		t.Errorf("unexpected CSV header or row count: %v", lines)
+	}
+
+	buf.Reset()
+	if err := surface.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded VolSurface
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Symbol != "SPY" || len(decoded.Points) != 8 || decoded.Volatility(60, 1) != surface.Volatility(60, 1) {
+		t.Errorf("surface did not survive a JSON round trip: %+v", decoded)
+	}
+}
@@ -0,0 +1,36 @@
+// Package backtest replays price history through trading strategies.
+//
+// A Strategy receives candles and trades through a Broker by submitting the same
+// tdameritrade.Order values that AccountsService.PlaceOrder accepts. Engine provides a simulated
+// Broker that fills those orders against later candles, and LiveBroker places them with TD Ameritrade,
+// so a strategy runs unchanged in a backtest and against a real account.
+package backtest
+
+import (
+	"context"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+// Broker accepts orders and reports holdings.
+type Broker interface {
+	// PlaceOrder submits an order. Simulated orders fill no earlier than the next candle.
+	PlaceOrder(ctx context.Context, order *tdameritrade.Order) error
+	// Positions returns the signed quantity held of each symbol; short positions are negative.
+	Positions(ctx context.Context) (map[string]float64, error)
+	// Cash returns the cash available in the account.
+	Cash(ctx context.Context) (float64, error)
+}
+
+// Strategy decides what to trade as each candle completes.
+type Strategy interface {
+	OnCandle(ctx context.Context, broker Broker, symbol string, candle tdameritrade.Candle) error
+}
+
+// StrategyFunc adapts a function to the Strategy interface.
+type StrategyFunc func(ctx context.Context, broker Broker, symbol string, candle tdameritrade.Candle) error
+
+// OnCandle calls f.
+func (f StrategyFunc) OnCandle(ctx context.Context, broker Broker, symbol string, candle tdameritrade.Candle) error {
+	return f(ctx, broker, symbol, candle)
+}
@@ -0,0 +1,362 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+// Commission is charged on every fill.
+type Commission struct {
+	PerOrder float64
+	PerShare float64
+}
+
+func (c Commission) charge(quantity float64) float64 {
+	return c.PerOrder + c.PerShare*quantity
+}
+
+// Engine runs a Strategy over historical candles with a simulated broker.
+//
+// Orders placed while handling a candle are filled against the following candles of the same symbol:
+// market orders at the open, limit orders at the limit or a better open, stop orders at the stop or a
+// worse open, and stop-limit orders become limit orders once their stop is touched. Only single leg
+// equity orders are supported. DAY orders expire at the end of the New York trading day they were placed,
+// but always get the candle after placement, so a DAY order placed on a daily candle works the next session.
+type Engine struct {
+	Strategy Strategy
+	// Candles holds the price history of each symbol, ordered by time.
+	Candles     map[string][]tdameritrade.Candle
+	InitialCash float64
+	Commission  Commission
+	// Slippage moves every fill against the order by this fraction of the price, e.g. 0.0005 for 5 basis points.
+	Slippage float64
+}
+
+// Fill is an executed order.
+type Fill struct {
+	Time        time.Time
+	Symbol      string
+	Instruction string
+	Quantity    float64
+	Price       float64
+	Commission  float64
+}
+
+// Trade is a closed position, or the closed part of one. Prices are per share.
+type Trade struct {
+	Symbol     string
+	EntryTime  time.Time
+	ExitTime   time.Time
+	Quantity   float64 // negative for short trades
+	EntryPrice float64
+	ExitPrice  float64
+	// Commission is the share of the entry and exit fills' commissions charged to this trade.
+	Commission float64
+	// PL is net of Commission.
+	PL float64
+}
+
+// EquityPoint is the account value after a candle.
+type EquityPoint struct {
+	Time   time.Time
+	Equity float64
+}
+
+// Result is the outcome of a backtest.
+type Result struct {
+	Fills  []Fill
+	Trades []Trade
+	Equity []EquityPoint
+	// Rejected lists orders the simulated broker could not accept or fill.
+	Rejected []string
+	Stats    Stats
+}
+
+type pendingOrder struct {
+	symbol    string
+	quantity  float64 // positive to buy, negative to sell
+	order     *tdameritrade.Order
+	placed    time.Time
+	seen      bool // whether a candle has been offered to the order
+	triggered bool
+}
+
+type holding struct {
+	quantity  float64
+	avgPrice  float64
+	entryTime time.Time
+	// commission is what opening the position cost that has not yet been charged to a trade.
+	commission float64
+}
+
+type simBroker struct {
+	engine   *Engine
+	now      time.Time
+	cash     float64
+	holdings map[string]*holding
+	last     map[string]float64
+	pending  []*pendingOrder
+	result   *Result
+}
+
+// Run replays every candle through the strategy in time order and returns the results.
+// It stops at the first error returned by the strategy.
+func (e *Engine) Run(ctx context.Context) (*Result, error) {
+	type event struct {
+		symbol string
+		candle tdameritrade.Candle
+	}
+	var events []event
+	for symbol, candles := range e.Candles {
+		for _, c := range candles {
+			events = append(events, event{symbol, c})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].candle.Datetime != events[j].candle.Datetime {
+			return events[i].candle.Datetime < events[j].candle.Datetime
+		}
+		return events[i].symbol < events[j].symbol
+	})
+
+	b := &simBroker{
+		engine:   e,
+		cash:     e.InitialCash,
+		holdings: map[string]*holding{},
+		last:     map[string]float64{},
+		result:   &Result{},
+	}
+	for i, ev := range events {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		b.now = ev.candle.Time()
+		b.fillPending(ev.symbol, ev.candle)
+		b.last[ev.symbol] = ev.candle.Close
+		if err := e.Strategy.OnCandle(ctx, b, ev.symbol, ev.candle); err != nil {
+			return nil, err
+		}
+		if i == len(events)-1 || events[i+1].candle.Datetime != ev.candle.Datetime {
+			b.result.Equity = append(b.result.Equity, EquityPoint{Time: b.now, Equity: b.equity()})
+		}
+	}
+
+	b.result.Stats = computeStats(e.InitialCash, b.result)
+	return b.result, nil
+}
+
+// PlaceOrder implements Broker.
+func (b *simBroker) PlaceOrder(ctx context.Context, order *tdameritrade.Order) error {
+	if order == nil {
+		return fmt.Errorf("order is nil")
+	}
+	if len(order.OrderLegCollection) != 1 {
+		return fmt.Errorf("only single leg orders are supported")
+	}
+	leg := order.OrderLegCollection[0]
+	equity, ok := leg.Instrument.Data.(*tdameritrade.Equity)
+	if !ok {
+		return fmt.Errorf("unsupported asset type %s", leg.Instrument.AssetType)
+	}
+	if _, ok := b.engine.Candles[equity.Symbol]; !ok {
+		return fmt.Errorf("no price history for %s", equity.Symbol)
+	}
+	if leg.Quantity <= 0 {
+		return fmt.Errorf("order quantity must be positive")
+	}
+
+	p := &pendingOrder{symbol: equity.Symbol, order: order, placed: b.now}
+	switch leg.Instruction {
+	case "BUY", "BUY_TO_COVER":
+		p.quantity = leg.Quantity
+	case "SELL", "SELL_SHORT":
+		p.quantity = -leg.Quantity
+	default:
+		return fmt.Errorf("unsupported instruction %s", leg.Instruction)
+	}
+	switch order.OrderType {
+	case "MARKET", "LIMIT", "STOP", "STOP_LIMIT":
+	default:
+		return fmt.Errorf("unsupported order type %s", order.OrderType)
+	}
+	b.pending = append(b.pending, p)
+	return nil
+}
+
+// Positions implements Broker.
+func (b *simBroker) Positions(ctx context.Context) (map[string]float64, error) {
+	positions := map[string]float64{}
+	for symbol, h := range b.holdings {
+		if h.quantity != 0 {
+			positions[symbol] = h.quantity
+		}
+	}
+	return positions, nil
+}
+
+// Cash implements Broker.
+func (b *simBroker) Cash(ctx context.Context) (float64, error) {
+	return b.cash, nil
+}
+
+func (b *simBroker) equity() float64 {
+	equity := b.cash
+	for symbol, h := range b.holdings {
+		equity += h.quantity * b.last[symbol]
+	}
+	return equity
+}
+
+// fillPending tries to fill the pending orders for symbol against a candle.
+func (b *simBroker) fillPending(symbol string, c tdameritrade.Candle) {
+	remaining := b.pending[:0]
+	for _, p := range b.pending {
+		if p.symbol != symbol {
+			remaining = append(remaining, p)
+			continue
+		}
+		if p.order.Duration != "GOOD_TILL_CANCEL" && p.seen && !sameDay(p.placed, b.now) {
+			b.result.Rejected = append(b.result.Rejected, fmt.Sprintf("%s %s order for %s expired unfilled", p.order.Duration, p.order.OrderType, symbol))
+			continue
+		}
+		p.seen = true
+		price, ok := fillPrice(p, c)
+		if !ok {
+			remaining = append(remaining, p)
+			continue
+		}
+		b.execute(p, price)
+	}
+	b.pending = remaining
+}
+
+// fillPrice returns the price an order fills at during a candle, before slippage.
+func fillPrice(p *pendingOrder, c tdameritrade.Candle) (float64, bool) {
+	buy := p.quantity > 0
+	order := p.order
+	switch order.OrderType {
+	case "MARKET":
+		return c.Open, true
+	case "LIMIT":
+		return limitFill(buy, order.Price, c)
+	case "STOP":
+		return stopFill(buy, order.StopPrice, c)
+	case "STOP_LIMIT":
+		if p.triggered {
+			return limitFill(buy, order.Price, c)
+		}
+		trigger, ok := stopFill(buy, order.StopPrice, c)
+		if !ok {
+			return 0, false
+		}
+		// Once triggered the order rests as a limit order. It only fills during the triggering
+		// candle if the trigger price itself is within the limit, since the path of prices within
+		// the candle is unknown.
+		p.triggered = true
+		if buy && trigger <= order.Price || !buy && trigger >= order.Price {
+			return trigger, true
+		}
+		return 0, false
+	}
+	return 0, false
+}
+
+// limitFill fills at the open if it is at or better than the limit, otherwise at the limit if the candle reaches it.
+func limitFill(buy bool, limit float64, c tdameritrade.Candle) (float64, bool) {
+	if buy {
+		if c.Open <= limit {
+			return c.Open, true
+		}
+		return limit, c.Low <= limit
+	}
+	if c.Open >= limit {
+		return c.Open, true
+	}
+	return limit, c.High >= limit
+}
+
+// stopFill fills at the open if it gaps through the stop, otherwise at the stop if the candle reaches it.
+func stopFill(buy bool, stop float64, c tdameritrade.Candle) (float64, bool) {
+	if buy {
+		if c.Open >= stop {
+			return c.Open, true
+		}
+		return stop, c.High >= stop
+	}
+	if c.Open <= stop {
+		return c.Open, true
+	}
+	return stop, c.Low <= stop
+}
+
+func (b *simBroker) execute(p *pendingOrder, price float64) {
+	slip := price * b.engine.Slippage
+	if p.quantity > 0 {
+		price += slip
+	} else {
+		price -= slip
+	}
+	qty := math.Abs(p.quantity)
+	commission := b.engine.Commission.charge(qty)
+	b.cash -= p.quantity*price + commission
+	b.result.Fills = append(b.result.Fills, Fill{
+		Time:        b.now,
+		Symbol:      p.symbol,
+		Instruction: p.order.OrderLegCollection[0].Instruction,
+		Quantity:    qty,
+		Price:       price,
+		Commission:  commission,
+	})
+
+	h, ok := b.holdings[p.symbol]
+	if !ok {
+		h = &holding{}
+		b.holdings[p.symbol] = h
+	}
+
+	// Close against the existing position first, at its average price.
+	if h.quantity != 0 && (h.quantity > 0) != (p.quantity > 0) {
+		closed := math.Min(math.Abs(h.quantity), qty)
+		signed := math.Copysign(closed, h.quantity)
+		entryCommission := h.commission * closed / math.Abs(h.quantity)
+		exitCommission := commission * closed / qty
+		b.result.Trades = append(b.result.Trades, Trade{
+			Symbol:     p.symbol,
+			EntryTime:  h.entryTime,
+			ExitTime:   b.now,
+			Quantity:   signed,
+			EntryPrice: h.avgPrice,
+			ExitPrice:  price,
+			Commission: entryCommission + exitCommission,
+			PL:         signed*(price-h.avgPrice) - entryCommission - exitCommission,
+		})
+		h.commission -= entryCommission
+		commission -= exitCommission
+		h.quantity -= signed
+		qty -= closed
+		if h.quantity == 0 {
+			h.avgPrice, h.commission = 0, 0
+		}
+	}
+
+	// Whatever is left opens or adds to a position.
+	if qty > 0 {
+		opening := math.Copysign(qty, p.quantity)
+		if h.quantity == 0 {
+			h.entryTime = b.now
+		}
+		h.avgPrice = (h.avgPrice*h.quantity + price*opening) / (h.quantity + opening)
+		h.quantity += opening
+		h.commission += commission
+	}
+}
+
+func sameDay(a, b time.Time) bool {
+	a, b = a.In(tdameritrade.MarketLocation), b.In(tdameritrade.MarketLocation)
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
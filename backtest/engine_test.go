@@ -0,0 +1,166 @@
+package backtest
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+func bars(prices ...[4]float64) []tdameritrade.Candle {
+	start := time.Date(2021, 1, 15, 9, 30, 0, 0, tdameritrade.MarketLocation)
+	candles := make([]tdameritrade.Candle, len(prices))
+	for i, p := range prices {
+		candles[i] = tdameritrade.Candle{
+			Datetime: int(tdameritrade.EpochMillis(start.Add(time.Duration(i) * time.Minute))),
+			Open:     p[0],
+			High:     p[1],
+			Low:      p[2],
+			Close:    p[3],
+		}
+	}
+	return candles
+}
+
+func order(orderType, instruction string, quantity, price, stop float64) *tdameritrade.Order {
+	return &tdameritrade.Order{
+		Session:           "NORMAL",
+		Duration:          "DAY",
+		OrderType:         orderType,
+		OrderStrategyType: "SINGLE",
+		Price:             price,
+		StopPrice:         stop,
+		OrderLegCollection: []*tdameritrade.OrderLegCollection{{
+			Instruction: instruction,
+			Quantity:    quantity,
+			Instrument: tdameritrade.Instrument{
+				AssetType: "EQUITY",
+				Data:      &tdameritrade.Equity{Symbol: "XYZ"},
+			},
+		}},
+	}
+}
+
+func TestEngineRoundTrip(t *testing.T) {
+	orders := map[int][]*tdameritrade.Order{
+		0: {order("MARKET", "BUY", 10, 0, 0)},
+		1: {order("LIMIT", "SELL", 10, 105, 0)},
+	}
+	var i int
+	strategy := StrategyFunc(func(ctx context.Context, broker Broker, symbol string, c tdameritrade.Candle) error {
+		for _, o := range orders[i] {
+			if err := broker.PlaceOrder(ctx, o); err != nil {
+				return err
+			}
+		}
+		i++
+		return nil
+	})
+
+	engine := &Engine{
+		Strategy: strategy,
+		Candles: map[string][]tdameritrade.Candle{"XYZ": bars(
+			[4]float64{99, 100, 98, 99},
+			[4]float64{100, 102, 99, 101},
+			[4]float64{101, 106, 100, 104},
+			[4]float64{104, 104, 103, 103},
+		)},
+		InitialCash: 10000,
+		Commission:  Commission{PerOrder: 1},
+	}
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Fills) != 2 || result.Fills[0].Price != 100 || result.Fills[1].Price != 105 {
+		t.Fatalf("expected a buy at the next open and a sell at the limit, got %+v", result.Fills)
+	}
+	if len(result.Trades) != 1 || result.Trades[0].PL != 48 || result.Trades[0].Commission != 2 {
+		t.Fatalf("expected one trade making 50 less 2 in commissions, got %+v", result.Trades)
+	}
+	if result.Stats.FinalEquity != 10048 || result.Stats.Trades != 1 || result.Stats.WinRate != 1 {
+		t.Errorf("unexpected stats %+v", result.Stats)
+	}
+	want := []float64{10000, 10009, 10048, 10048}
+	for i, p := range result.Equity {
+		if math.Abs(p.Equity-want[i]) > 1e-9 {
+			t.Errorf("equity[%d]: expected %v, got %v", i, want[i], p.Equity)
+		}
+	}
+}
+
+func TestFillPrice(t *testing.T) {
+	c := tdameritrade.Candle{Open: 100, High: 105, Low: 95, Close: 101}
+	tests := []struct {
+		name   string
+		order  *tdameritrade.Order
+		price  float64
+		filled bool
+	}{
+		{"buy limit below the low", order("LIMIT", "BUY", 1, 94, 0), 0, false},
+		{"buy limit inside the range", order("LIMIT", "BUY", 1, 96, 0), 96, true},
+		{"buy limit above the open", order("LIMIT", "BUY", 1, 102, 0), 100, true},
+		{"sell stop gapped through", order("STOP", "SELL", 1, 0, 101), 100, true},
+		{"buy stop inside the range", order("STOP", "BUY", 1, 0, 103), 103, true},
+		{"buy stop above the high", order("STOP", "BUY", 1, 0, 106), 0, false},
+		{"buy stop limit within the limit", order("STOP_LIMIT", "BUY", 1, 104, 103), 103, true},
+		{"buy stop limit beyond the limit", order("STOP_LIMIT", "BUY", 1, 102, 103), 0, false},
+	}
+	for _, test := range tests {
+		p := &pendingOrder{order: test.order, quantity: 1}
+		if test.order.OrderLegCollection[0].Instruction == "SELL" {
+			p.quantity = -1
+		}
+		price, filled := fillPrice(p, c)
+		if filled != test.filled || filled && price != test.price {
+			t.Errorf("%s: expected %v (%v), got %v (%v)", test.name, test.price, test.filled, price, filled)
+		}
+	}
+}
+
+func TestEngineDayOrdersOnDailyCandles(t *testing.T) {
+	start := time.Date(2021, 1, 11, 0, 0, 0, 0, tdameritrade.MarketLocation)
+	var candles []tdameritrade.Candle
+	for i, open := range []float64{100, 101, 102, 103} {
+		candles = append(candles, tdameritrade.Candle{
+			Datetime: int(tdameritrade.EpochMillis(start.AddDate(0, 0, i))),
+			Open:     open,
+			High:     open + 1,
+			Low:      open - 1,
+			Close:    open,
+		})
+	}
+
+	var i int
+	strategy := StrategyFunc(func(ctx context.Context, broker Broker, symbol string, c tdameritrade.Candle) error {
+		var err error
+		switch i {
+		case 0:
+			err = broker.PlaceOrder(ctx, order("MARKET", "BUY", 10, 0, 0))
+		case 1:
+			// out of reach of the next day's range
+			err = broker.PlaceOrder(ctx, order("LIMIT", "SELL", 10, 110, 0))
+		}
+		i++
+		return err
+	})
+	engine := &Engine{
+		Strategy:    strategy,
+		Candles:     map[string][]tdameritrade.Candle{"XYZ": candles},
+		InitialCash: 10000,
+	}
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Fills) != 1 || result.Fills[0].Price != 101 || !result.Fills[0].Time.Equal(start.AddDate(0, 0, 1)) {
+		t.Fatalf("expected the DAY market order to fill at the next day's open, got %+v", result.Fills)
+	}
+	if len(result.Rejected) != 1 {
+		t.Errorf("expected the unfilled DAY limit order to expire after one session, got %v", result.Rejected)
+	}
+}
@@ -0,0 +1,43 @@
+package backtest
+
+import (
+	"context"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+// LiveBroker is a Broker that trades a real TD Ameritrade account.
+type LiveBroker struct {
+	Accounts  *tdameritrade.AccountsService
+	AccountID string
+}
+
+// PlaceOrder implements Broker.
+func (b *LiveBroker) PlaceOrder(ctx context.Context, order *tdameritrade.Order) error {
+	_, err := b.Accounts.PlaceOrder(ctx, b.AccountID, order)
+	return err
+}
+
+// Positions implements Broker.
+func (b *LiveBroker) Positions(ctx context.Context) (map[string]float64, error) {
+	account, _, err := b.Accounts.GetAccount(ctx, b.AccountID, &tdameritrade.AccountOptions{Position: true})
+	if err != nil {
+		return nil, err
+	}
+	positions := map[string]float64{}
+	for _, p := range account.Positions {
+		if symbol := tdameritrade.InstrumentSymbol(p.Instrument); symbol != "" {
+			positions[symbol] += p.LongQuantity - p.ShortQuantity
+		}
+	}
+	return positions, nil
+}
+
+// Cash implements Broker.
+func (b *LiveBroker) Cash(ctx context.Context) (float64, error) {
+	account, _, err := b.Accounts.GetAccount(ctx, b.AccountID, nil)
+	if err != nil {
+		return 0, err
+	}
+	return account.CurrentBalances.CashAvailableForTrading, nil
+}
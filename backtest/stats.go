@@ -0,0 +1,81 @@
+package backtest
+
+import "math"
+
+// Stats summarizes a backtest.
+type Stats struct {
+	FinalEquity float64
+	// TotalReturn is the change in equity as a fraction of the initial cash.
+	TotalReturn float64
+	// MaxDrawdown is the largest fall from a peak in equity, as a fraction of the peak.
+	MaxDrawdown float64
+	// Sharpe is the mean return per candle divided by its standard deviation, not annualized.
+	Sharpe float64
+	Trades int
+	// WinRate and ProfitFactor are computed from each trade's PL, net of commissions.
+	WinRate      float64
+	ProfitFactor float64
+	Commissions  float64
+}
+
+func computeStats(initialCash float64, r *Result) Stats {
+	s := Stats{FinalEquity: initialCash, Trades: len(r.Trades)}
+	if n := len(r.Equity); n > 0 {
+		s.FinalEquity = r.Equity[n-1].Equity
+	}
+	if initialCash != 0 {
+		s.TotalReturn = (s.FinalEquity - initialCash) / initialCash
+	}
+
+	peak := initialCash
+	prev := initialCash
+	var returns []float64
+	for _, p := range r.Equity {
+		if p.Equity > peak {
+			peak = p.Equity
+		}
+		if peak > 0 {
+			s.MaxDrawdown = math.Max(s.MaxDrawdown, (peak-p.Equity)/peak)
+		}
+		if prev != 0 {
+			returns = append(returns, p.Equity/prev-1)
+		}
+		prev = p.Equity
+	}
+	if len(returns) > 1 {
+		var mean, variance float64
+		for _, r := range returns {
+			mean += r
+		}
+		mean /= float64(len(returns))
+		for _, r := range returns {
+			variance += (r - mean) * (r - mean)
+		}
+		if sd := math.Sqrt(variance / float64(len(returns)-1)); sd > 0 {
+			s.Sharpe = mean / sd
+		}
+	}
+
+	var wins int
+	var grossProfit, grossLoss float64
+	for _, t := range r.Trades {
+		if t.PL > 0 {
+			wins++
+			grossProfit += t.PL
+		} else {
+			grossLoss -= t.PL
+		}
+	}
+	if len(r.Trades) > 0 {
+		s.WinRate = float64(wins) / float64(len(r.Trades))
+	}
+	if grossLoss > 0 {
+		s.ProfitFactor = grossProfit / grossLoss
+	} else if grossProfit > 0 {
+		s.ProfitFactor = math.Inf(1)
+	}
+	for _, f := range r.Fills {
+		s.Commissions += f.Commission
+	}
+	return s
+}
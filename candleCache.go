@@ -0,0 +1,240 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CandleCache serves price history from files on disk and only asks TD Ameritrade for the parts
+// of a range it has not fetched before. Each symbol and frequency is stored in its own JSON file
+// under Dir, together with the time ranges that have already been fetched so that periods without
+// trading, such as weekends, are not requested again.
+type CandleCache struct {
+	Dir string
+
+	service *PriceHistoryService
+	mu      sync.Mutex
+}
+
+// CandleRange is a fetched time range in epoch milliseconds, including Start and excluding End.
+type CandleRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+type candleFile struct {
+	Fetched []CandleRange `json:"fetched"`
+	Candles []Candle      `json:"candles"`
+}
+
+// NewCandleCache returns a cache in front of the price history service that stores files in dir.
+func NewCandleCache(service *PriceHistoryService, dir string) *CandleCache {
+	return &CandleCache{Dir: dir, service: service}
+}
+
+// PriceHistory returns the candles for a symbol between from and to, fetching only missing segments.
+// frequencyType is one of minute, daily, weekly or monthly, as in PriceHistoryOptions.
+// Ranges from the start of the current bar on are never recorded as fetched, so today's candle,
+// or the current minute bar, is refreshed on every call.
+func (c *CandleCache) PriceHistory(ctx context.Context, symbol, frequencyType string, frequency int, from, to time.Time) ([]Candle, error) {
+	if !contains(frequencyType, validFrequencyTypes) {
+		return nil, fmt.Errorf("invalid frequencyType, must have the value of one of the following %v", validFrequencyTypes)
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("invalid date range, to must be after from")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := c.path(symbol, frequencyType, frequency)
+	file, err := readCandleFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	want := CandleRange{Start: EpochMillis(from), End: EpochMillis(to)}
+	missing := missingRanges(file.Fetched, want)
+	if len(missing) > 0 {
+		current := EpochMillis(currentBarStart(time.Now(), frequencyType, frequency))
+		for _, r := range missing {
+			candles, err := c.fetch(ctx, symbol, frequencyType, frequency, r)
+			if err != nil {
+				return nil, err
+			}
+			file.Candles = append(file.Candles, candles...)
+			if r.End > current {
+				r.End = current
+			}
+			if r.End > r.Start {
+				file.Fetched = append(file.Fetched, r)
+			}
+		}
+		file.Candles = dedupeCandles(file.Candles)
+		file.Fetched = mergeRanges(file.Fetched)
+		if err := writeCandleFile(path, file); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []Candle
+	for _, candle := range file.Candles {
+		if t := int64(candle.Datetime); t >= want.Start && t < want.End {
+			result = append(result, candle)
+		}
+	}
+	return result, nil
+}
+
+// currentBarStart returns the start of the bar containing t, which may still change. Weeks are
+// taken to start on Sunday so that the current weekly candle is always included.
+func currentBarStart(t time.Time, frequencyType string, frequency int) time.Time {
+	day := startOfDay(t)
+	switch frequencyType {
+	case "minute":
+		return t.Truncate(time.Duration(frequency) * time.Minute)
+	case "weekly":
+		return day.AddDate(0, 0, -int(day.Weekday()))
+	case "monthly":
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+func (c *CandleCache) fetch(ctx context.Context, symbol, frequencyType string, frequency int, r CandleRange) ([]Candle, error) {
+	from, to := FromEpochMillis(r.Start), FromEpochMillis(r.End)
+	if frequencyType == "minute" {
+		candles, _, err := c.service.PriceHistoryRange(ctx, symbol, from, to, frequency)
+		return candles, err
+	}
+
+	ph, _, err := c.service.PriceHistory(ctx, symbol, &PriceHistoryOptions{
+		PeriodType:    "year",
+		FrequencyType: frequencyType,
+		Frequency:     frequency,
+		StartDate:     from,
+		EndDate:       to,
+	})
+	if ph != nil && ph.Empty {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ph.Candles, nil
+}
+
+func (c *CandleCache) path(symbol, frequencyType string, frequency int) string {
+	// Symbols such as /ES and $SPX.X are not safe as file names.
+	name := strings.NewReplacer("/", "_", "$", "_", "\\", "_").Replace(symbol)
+	return filepath.Join(c.Dir, name, fmt.Sprintf("%s-%d.json", frequencyType, frequency))
+}
+
+func readCandleFile(path string) (*candleFile, error) {
+	file := new(candleFile)
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, file); err != nil {
+		return nil, fmt.Errorf("corrupt candle cache %s: %v", path, err)
+	}
+	return file, nil
+}
+
+// writeCandleFile replaces the file atomically so a crash never leaves a partial cache behind.
+func writeCandleFile(path string, file *candleFile) error {
+	b, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, b)
+}
+
+// writeFileAtomic writes b to a temporary file next to path and renames it into place.
+func writeFileAtomic(path string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// missingRanges returns the parts of want not covered by the sorted, merged fetched ranges.
+func missingRanges(fetched []CandleRange, want CandleRange) []CandleRange {
+	var missing []CandleRange
+	start := want.Start
+	for _, r := range fetched {
+		if r.End <= start {
+			continue
+		}
+		if r.Start >= want.End {
+			break
+		}
+		if r.Start > start {
+			missing = append(missing, CandleRange{Start: start, End: r.Start})
+		}
+		start = r.End
+	}
+	if start < want.End {
+		missing = append(missing, CandleRange{Start: start, End: want.End})
+	}
+	return missing
+}
+
+// mergeRanges sorts ranges and joins those that overlap or touch.
+func mergeRanges(ranges []CandleRange) []CandleRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+	var merged []CandleRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.Start <= merged[n-1].End {
+			if r.End > merged[n-1].End {
+				merged[n-1].End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// dedupeCandles orders candles by time, keeping the most recently added candle for each time.
+func dedupeCandles(candles []Candle) []Candle {
+	byTime := make(map[int]Candle, len(candles))
+	for _, c := range candles {
+		byTime[c.Datetime] = c
+	}
+	out := make([]Candle, 0, len(byTime))
+	for _, c := range byTime {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Datetime < out[j].Datetime
+	})
+	return out
+}
@@ -0,0 +1,108 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCandleCacheFetchesOnlyMissingRanges(t *testing.T) {
+	var requests []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		requests = append(requests, q.Get("startDate")+"-"+q.Get("endDate"))
+		start, _ := strconv.Atoi(q.Get("startDate"))
+		end, _ := strconv.Atoi(q.Get("endDate"))
+		var candles []Candle
+		for t := start; t < end; t += int(24 * time.Hour / time.Millisecond) {
+			candles = append(candles, Candle{Datetime: t, Close: float64(t)})
+		}
+		json.NewEncoder(w).Encode(PriceHistory{Candles: candles, Symbol: "SPY"})
+	})
+
+	dir := t.TempDir()
+
+	cache := NewCandleCache(c.PriceHistory, dir)
+	ctx := context.Background()
+	day := time.Date(2020, 6, 1, 0, 0, 0, 0, MarketLocation)
+
+	candles, err := cache.PriceHistory(ctx, "SPY", "daily", 1, day, day.AddDate(0, 0, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 10 || len(requests) != 1 {
+		t.Fatalf("expected 10 candles from 1 request, got %d from %d", len(candles), len(requests))
+	}
+
+	candles, err = cache.PriceHistory(ctx, "SPY", "daily", 1, day.AddDate(0, 0, 5), day.AddDate(0, 0, 15))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 10 || len(requests) != 2 {
+		t.Fatalf("expected 10 candles after 2 requests, got %d after %d", len(candles), len(requests))
+	}
+	want := strconv.FormatInt(EpochMillis(day.AddDate(0, 0, 10)), 10) + "-" + strconv.FormatInt(EpochMillis(day.AddDate(0, 0, 15)), 10)
+	if requests[1] != want {
+		t.Errorf("expected only the missing range %s to be fetched, got %s", want, requests[1])
+	}
+
+	if _, err := cache.PriceHistory(ctx, "SPY", "daily", 1, day.AddDate(0, 0, 2), day.AddDate(0, 0, 12)); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 {
+		t.Errorf("expected a fully cached range to be served from disk, got %d requests", len(requests))
+	}
+}
+
+func TestCandleCacheRefreshesCurrentBar(t *testing.T) {
+	today := startOfDay(time.Now())
+	from := today.AddDate(0, 0, -5)
+	var starts []int64
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.ParseInt(r.URL.Query().Get("startDate"), 10, 64)
+		starts = append(starts, start)
+		candles := []Candle{{Datetime: int(EpochMillis(today)), Close: float64(len(starts))}}
+		if start == EpochMillis(from) {
+			candles = append([]Candle{{Datetime: int(start), Close: 100}}, candles...)
+		}
+		json.NewEncoder(w).Encode(PriceHistory{Candles: candles, Symbol: "SPY"})
+	})
+	cache := NewCandleCache(c.PriceHistory, t.TempDir())
+	ctx := context.Background()
+
+	if _, err := cache.PriceHistory(ctx, "SPY", "daily", 1, from, today.AddDate(0, 0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	candles, err := cache.PriceHistory(ctx, "SPY", "daily", 1, from, today.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(starts) != 2 || starts[1] != EpochMillis(today) {
+		t.Fatalf("expected the second call to fetch from the start of today, got requests from %v", starts)
+	}
+	if len(candles) != 2 || candles[1].Close != 2 {
+		t.Errorf("expected today's candle to be replaced by the refetched one, got %+v", candles)
+	}
+}
+
+func TestCurrentBarStart(t *testing.T) {
+	now := time.Date(2021, 1, 14, 10, 37, 20, 0, MarketLocation) // a Thursday
+	tests := []struct {
+		frequencyType string
+		frequency     int
+		want          time.Time
+	}{
+		{"minute", 5, time.Date(2021, 1, 14, 10, 35, 0, 0, MarketLocation)},
+		{"daily", 1, time.Date(2021, 1, 14, 0, 0, 0, 0, MarketLocation)},
+		{"weekly", 1, time.Date(2021, 1, 10, 0, 0, 0, 0, MarketLocation)},
+		{"monthly", 1, time.Date(2021, 1, 1, 0, 0, 0, 0, MarketLocation)},
+	}
+	for _, test := range tests {
+		if got := currentBarStart(now, test.frequencyType, test.frequency); !got.Equal(test.want) {
+			t.Errorf("%s: expected %v, got %v", test.frequencyType, test.want, got)
+		}
+	}
+}
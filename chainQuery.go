@@ -0,0 +1,230 @@
+package tdameritrade
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expiration is an expiration date in an option chain.
+type Expiration struct {
+	// Key is the ExpDateMap key, e.g. "2021-01-15:30".
+	Key string
+	// Date is the calendar date of the expiration, at midnight UTC.
+	Date             time.Time
+	DaysToExpiration int
+}
+
+// StrikePair is the call and put at the same strike and expiration.
+// Either side is nil if the chain does not contain it.
+type StrikePair struct {
+	Strike float64
+	Call   *ExpDateOption
+	Put    *ExpDateOption
+}
+
+// ChainEntry is a single contract of a flattened option chain.
+type ChainEntry struct {
+	Expiration Expiration
+	Strike     float64
+	Option     *ExpDateOption
+}
+
+// ChainEntries is a flattened option chain. It sorts by expiration, then strike, with calls before puts,
+// then by symbol.
+type ChainEntries []ChainEntry
+
+func (e ChainEntries) Len() int      { return len(e) }
+func (e ChainEntries) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e ChainEntries) Less(i, j int) bool {
+	if !e[i].Expiration.Date.Equal(e[j].Expiration.Date) {
+		return e[i].Expiration.Date.Before(e[j].Expiration.Date)
+	}
+	if e[i].Strike != e[j].Strike {
+		return e[i].Strike < e[j].Strike
+	}
+	if e[i].Option.PutCall != e[j].Option.PutCall {
+		return e[i].Option.PutCall < e[j].Option.PutCall
+	}
+	return e[i].Option.Symbol < e[j].Option.Symbol
+}
+
+// ParseExpiration parses an ExpDateMap key such as "2021-01-15:30".
+func ParseExpiration(key string) (Expiration, error) {
+	parts := strings.SplitN(key, ":", 2)
+	date, err := time.Parse("2006-01-02", parts[0])
+	if err != nil {
+		return Expiration{}, fmt.Errorf("invalid expiration %q: %v", key, err)
+	}
+	exp := Expiration{Key: key, Date: date}
+	if len(parts) == 2 {
+		if exp.DaysToExpiration, err = strconv.Atoi(parts[1]); err != nil {
+			return Expiration{}, fmt.Errorf("invalid expiration %q: %v", key, err)
+		}
+	}
+	return exp, nil
+}
+
+// Expirations lists the expirations found in either side of the chain, earliest first.
+func (c *Chains) Expirations() ([]Expiration, error) {
+	seen := map[string]bool{}
+	var expirations []Expiration
+	for _, m := range []ExpDateMap{c.CallExpDateMap, c.PutExpDateMap} {
+		for key := range m {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			exp, err := ParseExpiration(key)
+			if err != nil {
+				return nil, err
+			}
+			expirations = append(expirations, exp)
+		}
+	}
+	sort.Slice(expirations, func(i, j int) bool {
+		return expirations[i].Date.Before(expirations[j].Date)
+	})
+	return expirations, nil
+}
+
+// Strikes lists the strikes available at an expiration on either side of the chain, lowest first.
+func (c *Chains) Strikes(expiration string) ([]float64, error) {
+	seen := map[string]bool{}
+	var strikes []float64
+	for _, m := range []ExpDateMap{c.CallExpDateMap, c.PutExpDateMap} {
+		for key := range m[expiration] {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			strike, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid strike %q: %v", key, err)
+			}
+			strikes = append(strikes, strike)
+		}
+	}
+	sort.Float64s(strikes)
+	return strikes, nil
+}
+
+// NearestStrikes returns up to n strikes at an expiration closest to the underlying price, lowest first.
+func (c *Chains) NearestStrikes(expiration string, n int) ([]float64, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid n, must be positive")
+	}
+	strikes, err := c.Strikes(expiration)
+	if err != nil {
+		return nil, err
+	}
+	price := c.SpotPrice()
+	sort.SliceStable(strikes, func(i, j int) bool {
+		return math.Abs(strikes[i]-price) < math.Abs(strikes[j]-price)
+	})
+	if n < len(strikes) {
+		strikes = strikes[:n]
+	}
+	sort.Float64s(strikes)
+	return strikes, nil
+}
+
+// Pairs returns the call and put at each strike of an expiration, lowest strike first.
+func (c *Chains) Pairs(expiration string) ([]StrikePair, error) {
+	strikes, err := c.Strikes(expiration)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]StrikePair, 0, len(strikes))
+	for _, strike := range strikes {
+		pairs = append(pairs, StrikePair{
+			Strike: strike,
+			Call:   c.CallExpDateMap.find(expiration, strike),
+			Put:    c.PutExpDateMap.find(expiration, strike),
+		})
+	}
+	return pairs, nil
+}
+
+// ByDelta returns the option at an expiration whose delta is closest to delta. Ties go to the
+// lower strike, then the lower symbol.
+// putCall is "CALL" or "PUT"; put deltas are negative, so pass e.g. -0.25 for a 25 delta put.
+func (c *Chains) ByDelta(expiration, putCall string, delta float64) (*ExpDateOption, error) {
+	var m ExpDateMap
+	switch putCall {
+	case "CALL":
+		m = c.CallExpDateMap
+	case "PUT":
+		m = c.PutExpDateMap
+	default:
+		return nil, fmt.Errorf("invalid putCall %q, must be CALL or PUT", putCall)
+	}
+
+	var candidates []*ExpDateOption
+	for _, options := range m[expiration] {
+		for i := range options {
+			candidates = append(candidates, &options[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no %s options at expiration %s", putCall, expiration)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if da, db := math.Abs(a.Delta-delta), math.Abs(b.Delta-delta); da != db {
+			return da < db
+		}
+		if a.StrikePrice != b.StrikePrice {
+			return a.StrikePrice < b.StrikePrice
+		}
+		return a.Symbol < b.Symbol
+	})
+	return candidates[0], nil
+}
+
+// Flatten returns every contract in the chain as a sorted slice.
+func (c *Chains) Flatten() (ChainEntries, error) {
+	var entries ChainEntries
+	for _, m := range []ExpDateMap{c.CallExpDateMap, c.PutExpDateMap} {
+		for key, strikes := range m {
+			exp, err := ParseExpiration(key)
+			if err != nil {
+				return nil, err
+			}
+			for _, options := range strikes {
+				for i := range options {
+					entries = append(entries, ChainEntry{
+						Expiration: exp,
+						Strike:     options[i].StrikePrice,
+						Option:     &options[i],
+					})
+				}
+			}
+		}
+	}
+	sort.Stable(entries)
+	return entries, nil
+}
+
+// SpotPrice returns the price of the underlying, falling back to the underlying quote's mark
+// when the chain has no underlying price.
+func (c *Chains) SpotPrice() float64 {
+	if c.UnderlyingPrice != 0 {
+		return c.UnderlyingPrice
+	}
+	return c.Underlying.Mark
+}
+
+// find returns the first option at an expiration and strike.
+// Strike keys are formatted by TD Ameritrade, so they are compared numerically.
+func (m ExpDateMap) find(expiration string, strike float64) *ExpDateOption {
+	for key, options := range m[expiration] {
+		if s, err := strconv.ParseFloat(key, 64); err == nil && s == strike && len(options) > 0 {
+			return &options[0]
+		}
+	}
+	return nil
+}
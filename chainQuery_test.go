@@ -0,0 +1,104 @@
+package tdameritrade
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testChainJSON = `{
+	"symbol": "AAPL",
+	"underlyingPrice": 131.2,
+	"callExpDateMap": {
+		"2021-01-22:7": {
+			"130.0": [{"putCall": "CALL", "symbol": "AAPL_012221C130", "strikePrice": 130.0, "delta": 0.58}],
+			"135.0": [{"putCall": "CALL", "symbol": "AAPL_012221C135", "strikePrice": 135.0, "delta": 0.25}]
+		},
+		"2021-01-15:0": {
+			"130.0": [{"putCall": "CALL", "symbol": "AAPL_011521C130", "strikePrice": 130.0, "delta": 0.9}]
+		}
+	},
+	"putExpDateMap": {
+		"2021-01-22:7": {
+			"125.0": [{"putCall": "PUT", "symbol": "AAPL_012221P125", "strikePrice": 125.0, "delta": -0.2}],
+			"130.0": [{"putCall": "PUT", "symbol": "AAPL_012221P130", "strikePrice": 130.0, "delta": -0.42}]
+		}
+	}
+}`
+
+func testChain(t *testing.T) *Chains {
+	chains := new(Chains)
+	if err := json.Unmarshal([]byte(testChainJSON), chains); err != nil {
+		t.Fatal(err)
+	}
+	return chains
+}
+
+func TestChainsExpirations(t *testing.T) {
+	expirations, err := testChain(t).Expirations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expirations) != 2 || expirations[0].Key != "2021-01-15:0" || expirations[1].DaysToExpiration != 7 {
+		t.Errorf("unexpected expirations %+v", expirations)
+	}
+}
+
+func TestChainsNearestStrikesAndPairs(t *testing.T) {
+	chains := testChain(t)
+	strikes, err := chains.NearestStrikes("2021-01-22:7", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(strikes) != 2 || strikes[0] != 130 || strikes[1] != 135 {
+		t.Errorf("unexpected nearest strikes %v", strikes)
+	}
+	for _, n := range []int{0, -1} {
+		if _, err := chains.NearestStrikes("2021-01-22:7", n); err == nil {
+			t.Errorf("expected an error for n = %d", n)
+		}
+	}
+
+	pairs, err := chains.Pairs("2021-01-22:7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 3 || pairs[0].Call != nil || pairs[1].Call == nil || pairs[1].Put == nil || pairs[2].Put != nil {
+		t.Errorf("unexpected pairs %+v", pairs)
+	}
+}
+
+func TestChainsByDeltaAndFlatten(t *testing.T) {
+	chains := testChain(t)
+	put, err := chains.ByDelta("2021-01-22:7", "PUT", -0.25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if put.Symbol != "AAPL_012221P125" {
+		t.Errorf("expected 25 delta put to be AAPL_012221P125, got %s", put.Symbol)
+	}
+
+	// 0.58 and 0.25 are equally far from 0.415, so the lower strike wins every time
+	for i := 0; i < 20; i++ {
+		call, err := chains.ByDelta("2021-01-22:7", "CALL", 0.415)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if call.Symbol != "AAPL_012221C130" {
+			t.Fatalf("expected a delta tie to go to AAPL_012221C130, got %s", call.Symbol)
+		}
+	}
+
+	entries, err := chains.Flatten()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"AAPL_011521C130", "AAPL_012221P125", "AAPL_012221C130", "AAPL_012221P130", "AAPL_012221C135"}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(entries))
+	}
+	for i, e := range entries {
+		if e.Option.Symbol != want[i] {
+			t.Errorf("entry %d: expected %s, got %s", i, want[i], e.Option.Symbol)
+		}
+	}
+}
@@ -3,7 +3,23 @@ package tdameritrade
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"time"
+
+	"github.com/google/go-querystring/query"
+)
+
+var (
+	validContractTypes = []string{"CALL", "PUT", "ALL"}
+	validStrategies    = []string{"SINGLE", "ANALYTICAL", "COVERED", "VERTICAL", "CALENDAR", "STRANGLE", "STRADDLE", "BUTTERFLY", "CONDOR", "DIAGONAL", "COLLAR", "ROLL"}
+	validRanges        = []string{"ITM", "NTM", "OTM", "SAK", "SBK", "SNK", "ALL"}
+	validExpMonths     = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC", "ALL"}
+	validOptionTypes   = []string{"S", "NS", "ALL"}
+	spreadStrategies   = []string{"VERTICAL", "CALENDAR", "STRANGLE", "STRADDLE", "BUTTERFLY", "CONDOR", "DIAGONAL", "COLLAR", "ROLL"}
+)
+
+const (
+	analyticalStrategy    = "ANALYTICAL"
+	defaultChainsStrategy = "SINGLE"
 )
 
 // ChainsService handles communication with the chains related methods of
@@ -110,11 +126,55 @@ type Chains struct {
 	PutExpDateMap     ExpDateMap `json:"putExpDateMap"`
 }
 
-// Users must provide the required URL queryValues for this function to work.
+// OptionChainOptions is parsed and translated to query options in the https request.
 // TD Ameritrade url values: https://developer.tdameritrade.com/option-chains/apis/get/marketdata/chains
-// Instructions for using url.Values: https://golang.org/pkg/net/url/#Values
-func (s *ChainsService) GetChains(ctx context.Context, queryValues url.Values) (*Chains, *Response, error) {
-	u := fmt.Sprintf("marketdata/chains?%s", queryValues.Encode())
+type OptionChainOptions struct {
+	Symbol string `url:"symbol"`
+	// CALL, PUT or ALL
+	ContractType string `url:"contractType,omitempty"`
+	// number of strikes above and below the at-the-money price
+	StrikeCount   int  `url:"strikeCount,omitempty"`
+	IncludeQuotes bool `url:"includeQuotes,omitempty"`
+	// SINGLE, ANALYTICAL or one of the spread strategies
+	Strategy string `url:"strategy,omitempty"`
+	// strike interval for spread strategy chains
+	Interval float64 `url:"interval,omitempty"`
+	Strike   float64 `url:"strike,omitempty"`
+	// ITM, NTM, OTM, SAK, SBK, SNK or ALL
+	Range    string    `url:"range,omitempty"`
+	FromDate time.Time `url:"-"`
+	ToDate   time.Time `url:"-"`
+	// the following are only used by the ANALYTICAL strategy
+	Volatility       float64 `url:"volatility,omitempty"`
+	UnderlyingPrice  float64 `url:"underlyingPrice,omitempty"`
+	InterestRate     float64 `url:"interestRate,omitempty"`
+	DaysToExpiration int     `url:"daysToExpiration,omitempty"`
+	// JAN through DEC, or ALL
+	ExpMonth string `url:"expMonth,omitempty"`
+	// S (standard), NS (non-standard) or ALL
+	OptionType string `url:"optionType,omitempty"`
+}
+
+// GetChains gets the option chain for a symbol
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/option-chains/apis/get/marketdata/chains
+func (s *ChainsService) GetChains(ctx context.Context, opts *OptionChainOptions) (*Chains, *Response, error) {
+	if opts == nil {
+		return nil, nil, fmt.Errorf("no options present")
+	}
+	if err := opts.validate(); err != nil {
+		return nil, nil, err
+	}
+	q, err := query.Values(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !opts.FromDate.IsZero() {
+		q.Set("fromDate", opts.FromDate.Format("2006-01-02"))
+	}
+	if !opts.ToDate.IsZero() {
+		q.Set("toDate", opts.ToDate.Format("2006-01-02"))
+	}
+	u := fmt.Sprintf("marketdata/chains?%s", q.Encode())
 
 	req, err := s.client.NewRequest("GET", u, nil)
 
@@ -131,3 +191,50 @@ func (s *ChainsService) GetChains(ctx context.Context, queryValues url.Values) (
 
 	return chains, resp, nil
 }
+
+func (opts *OptionChainOptions) validate() error {
+	if opts.Symbol == "" {
+		return fmt.Errorf("no symbol present")
+	}
+	if opts.ContractType != "" && !contains(opts.ContractType, validContractTypes) {
+		return fmt.Errorf("invalid contractType, must have the value of one of the following %v", validContractTypes)
+	}
+	if opts.Strategy != "" {
+		if !contains(opts.Strategy, validStrategies) {
+			return fmt.Errorf("invalid strategy, must have the value of one of the following %v", validStrategies)
+		}
+	} else {
+		opts.Strategy = defaultChainsStrategy
+	}
+	if opts.Range != "" && !contains(opts.Range, validRanges) {
+		return fmt.Errorf("invalid range, must have the value of one of the following %v", validRanges)
+	}
+	if opts.ExpMonth != "" && !contains(opts.ExpMonth, validExpMonths) {
+		return fmt.Errorf("invalid expMonth, must have the value of one of the following %v", validExpMonths)
+	}
+	if opts.OptionType != "" && !contains(opts.OptionType, validOptionTypes) {
+		return fmt.Errorf("invalid optionType, must have the value of one of the following %v", validOptionTypes)
+	}
+	if opts.StrikeCount < 0 {
+		return fmt.Errorf("invalid strikeCount, must not be negative")
+	}
+	if !opts.FromDate.IsZero() && !opts.ToDate.IsZero() && opts.ToDate.Before(opts.FromDate) {
+		return fmt.Errorf("invalid date range, toDate is before fromDate")
+	}
+
+	analytical := opts.Volatility != 0 || opts.UnderlyingPrice != 0 || opts.InterestRate != 0 || opts.DaysToExpiration != 0
+	if analytical && opts.Strategy != analyticalStrategy {
+		return fmt.Errorf("volatility, underlyingPrice, interestRate and daysToExpiration require the %s strategy", analyticalStrategy)
+	}
+	if opts.Volatility < 0 || opts.UnderlyingPrice < 0 || opts.DaysToExpiration < 0 {
+		return fmt.Errorf("volatility, underlyingPrice and daysToExpiration must not be negative")
+	}
+	if opts.Interval != 0 && !contains(opts.Strategy, spreadStrategies) {
+		return fmt.Errorf("interval requires one of the following strategies %v", spreadStrategies)
+	}
+	if opts.Interval < 0 {
+		return fmt.Errorf("invalid interval, must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestGetChainsQuery(t *testing.T) {
+	var query url.Values
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		json.NewEncoder(w).Encode(Chains{Symbol: "AAPL"})
+	})
+
+	_, _, err := c.Chains.GetChains(context.Background(), &OptionChainOptions{
+		Symbol:        "AAPL",
+		ContractType:  "CALL",
+		StrikeCount:   4,
+		IncludeQuotes: true,
+		Strategy:      "VERTICAL",
+		Interval:      2.5,
+		FromDate:      time.Date(2021, 1, 15, 0, 0, 0, 0, MarketLocation),
+		ToDate:        time.Date(2021, 2, 19, 0, 0, 0, 0, MarketLocation),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"symbol":        "AAPL",
+		"contractType":  "CALL",
+		"strikeCount":   "4",
+		"includeQuotes": "true",
+		"strategy":      "VERTICAL",
+		"interval":      "2.5",
+		"fromDate":      "2021-01-15",
+		"toDate":        "2021-02-19",
+	}
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("expected %s=%s, got %q", key, value, got)
+		}
+	}
+	for _, key := range []string{"volatility", "range", "expMonth"} {
+		if _, ok := query[key]; ok {
+			t.Errorf("expected unset %s to be omitted", key)
+		}
+	}
+
+	_, _, err = c.Chains.GetChains(context.Background(), &OptionChainOptions{Symbol: "AAPL"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("strategy") != defaultChainsStrategy {
+		t.Errorf("expected the default strategy, got %q", query.Get("strategy"))
+	}
+}
+
+func TestOptionChainOptionsValidate(t *testing.T) {
+	day := time.Date(2021, 1, 15, 0, 0, 0, 0, MarketLocation)
+	tests := map[string]*OptionChainOptions{
+		"missing symbol":                 {},
+		"invalid contract type":          {Symbol: "AAPL", ContractType: "BOTH"},
+		"analytical without ANALYTICAL":  {Symbol: "AAPL", Volatility: 30},
+		"interval without spread":        {Symbol: "AAPL", Interval: 5},
+		"interval with single":           {Symbol: "AAPL", Strategy: "SINGLE", Interval: 5},
+		"toDate before fromDate":         {Symbol: "AAPL", FromDate: day, ToDate: day.AddDate(0, 0, -1)},
+		"negative strike count":          {Symbol: "AAPL", StrikeCount: -1},
+		"negative analytical volatility": {Symbol: "AAPL", Strategy: "ANALYTICAL", Volatility: -1},
+	}
+	for name, opts := range tests {
+		if err := opts.validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	ok := &OptionChainOptions{Symbol: "AAPL", Strategy: "ANALYTICAL", Volatility: 30, DaysToExpiration: 10, FromDate: day, ToDate: day}
+	if err := ok.validate(); err != nil {
+		t.Errorf("expected valid analytical options, got %v", err)
+	}
+}
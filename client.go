@@ -44,6 +44,17 @@ type Response struct {
 	// TODO add additional items if needed
 }
 
+// ErrorResponse is returned when the TD-Ameritrade API responds with a non-2xx status code.
+// Its message is the raw body of the response.
+type ErrorResponse struct {
+	Response *http.Response
+	Message  string
+}
+
+func (r *ErrorResponse) Error() string {
+	return r.Message
+}
+
 // NewClient returns a new TD-Ameritrade API client. If a nil httpClient is
 // provided, a new http.Client will be used. To use API methods which require
 // authentication, provide an http.Client that will perform the authentication
@@ -131,7 +142,7 @@ func checkResponse(r *http.Response) error {
 		return nil
 	}
 	errMsg, _ := ioutil.ReadAll(r.Body)
-	return errors.New(string(errMsg))
+	return &ErrorResponse{Response: r, Message: string(errMsg)}
 }
 
 func newResponse(r *http.Response) *Response {
@@ -0,0 +1,23 @@
+package tdameritrade
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient returns a client whose requests are served by handler. The server is closed when the test ends.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateBaseURL(server.URL + "/"); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
@@ -0,0 +1,185 @@
+package tdameritrade
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The columnar format starts with columnarMagic followed by blocks of up to columnarBlockSize candles.
+// Each block is the number of candles as a uvarint, the datetimes as a varint of the first value
+// followed by varint deltas, then the open, high, low, close and volume columns as little endian float64s.
+// A block count of zero marks the end of the data.
+const (
+	columnarMagic     = "TDCANDL1"
+	columnarBlockSize = 4096
+)
+
+type columnarEncoder struct {
+	w     *bufio.Writer
+	block []Candle
+}
+
+func newColumnarEncoder(w io.Writer) (*columnarEncoder, error) {
+	e := &columnarEncoder{w: bufio.NewWriter(w), block: make([]Candle, 0, columnarBlockSize)}
+	if _, err := e.w.WriteString(columnarMagic); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *columnarEncoder) Encode(c Candle) error {
+	e.block = append(e.block, c)
+	if len(e.block) == columnarBlockSize {
+		return e.flushBlock()
+	}
+	return nil
+}
+
+func (e *columnarEncoder) Close() error {
+	if err := e.flushBlock(); err != nil {
+		return err
+	}
+	if err := e.writeUvarint(0); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+func (e *columnarEncoder) flushBlock() error {
+	if len(e.block) == 0 {
+		return nil
+	}
+	if err := e.writeUvarint(uint64(len(e.block))); err != nil {
+		return err
+	}
+
+	var prev int64
+	for _, c := range e.block {
+		if err := e.writeVarint(int64(c.Datetime) - prev); err != nil {
+			return err
+		}
+		prev = int64(c.Datetime)
+	}
+
+	columns := []func(Candle) float64{
+		func(c Candle) float64 { return c.Open },
+		func(c Candle) float64 { return c.High },
+		func(c Candle) float64 { return c.Low },
+		func(c Candle) float64 { return c.Close },
+		func(c Candle) float64 { return c.Volume },
+	}
+	var buf [8]byte
+	for _, column := range columns {
+		for _, c := range e.block {
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(column(c)))
+			if _, err := e.w.Write(buf[:]); err != nil {
+				return err
+			}
+		}
+	}
+	e.block = e.block[:0]
+	return nil
+}
+
+func (e *columnarEncoder) writeUvarint(v uint64) error {
+	var buf [binary.MaxVarintLen64]byte
+	_, err := e.w.Write(buf[:binary.PutUvarint(buf[:], v)])
+	return err
+}
+
+func (e *columnarEncoder) writeVarint(v int64) error {
+	var buf [binary.MaxVarintLen64]byte
+	_, err := e.w.Write(buf[:binary.PutVarint(buf[:], v)])
+	return err
+}
+
+type columnarDecoder struct {
+	r     *bufio.Reader
+	block []Candle
+	next  int
+	done  bool
+}
+
+func newColumnarDecoder(r io.Reader) (*columnarDecoder, error) {
+	d := &columnarDecoder{r: bufio.NewReader(r)}
+	magic := make([]byte, len(columnarMagic))
+	if _, err := io.ReadFull(d.r, magic); err != nil {
+		return nil, err
+	}
+	if string(magic) != columnarMagic {
+		return nil, fmt.Errorf("not a columnar candle file")
+	}
+	return d, nil
+}
+
+func (d *columnarDecoder) Decode() (Candle, error) {
+	if d.next == len(d.block) {
+		if d.done {
+			return Candle{}, io.EOF
+		}
+		if err := d.readBlock(); err != nil {
+			return Candle{}, err
+		}
+		if d.done {
+			return Candle{}, io.EOF
+		}
+	}
+	c := d.block[d.next]
+	d.next++
+	return c, nil
+}
+
+func (d *columnarDecoder) readBlock() error {
+	n, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+	if n == 0 {
+		d.done = true
+		return nil
+	}
+	if n > columnarBlockSize {
+		return fmt.Errorf("corrupt columnar block of %d candles", n)
+	}
+
+	d.block = make([]Candle, n)
+	d.next = 0
+	var prev int64
+	for i := range d.block {
+		delta, err := binary.ReadVarint(d.r)
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		prev += delta
+		d.block[i].Datetime = int(prev)
+	}
+
+	columns := []func(*Candle) *float64{
+		func(c *Candle) *float64 { return &c.Open },
+		func(c *Candle) *float64 { return &c.High },
+		func(c *Candle) *float64 { return &c.Low },
+		func(c *Candle) *float64 { return &c.Close },
+		func(c *Candle) *float64 { return &c.Volume },
+	}
+	var buf [8]byte
+	for _, column := range columns {
+		for i := range d.block {
+			if _, err := io.ReadFull(d.r, buf[:]); err != nil {
+				return unexpectedEOF(err)
+			}
+			*column(&d.block[i]) = math.Float64frombits(binary.LittleEndian.Uint64(buf[:]))
+		}
+	}
+	return nil
+}
+
+// unexpectedEOF reports a file that ends inside a block as truncated rather than complete.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
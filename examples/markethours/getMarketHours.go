@@ -41,13 +41,13 @@ func main() {
 		log.Fatal(err)
 	}
 
-	hours, _, err := c.MarketHours.GetMarketHours(ctx, "Equity", time.Now())
+	hours, _, err := c.MarketHours.GetMarketHours(ctx, tdameritrade.MarketEquity, time.Now())
 	if err != nil {
 		log.Fatal(err)
 	}
 	fmt.Printf("%+v\n", (*hours)["equity"]["EQ"])
 
-	hours, _, err = c.MarketHours.GetMarketHoursMulti(ctx, "EQUITY,OPTION", time.Now())
+	hours, _, err = c.MarketHours.GetMarketHoursMulti(ctx, time.Now(), tdameritrade.MarketEquity, tdameritrade.MarketOption)
 	if err != nil {
 		log.Fatal(err)
 	}
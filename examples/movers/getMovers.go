@@ -40,7 +40,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	ph, _, err := c.Mover.Mover(ctx, "$SPX.X", nil)
+	ph, _, err := c.Mover.Mover(ctx, tdameritrade.IndexSPX, nil)
 	if err != nil {
 		log.Fatal(err)
 	}
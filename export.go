@@ -0,0 +1,326 @@
+package tdameritrade
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format is a file format for exporting price history and quotes.
+type Format int
+
+const (
+	// FormatCSV is comma separated values with a header row named after the JSON fields.
+	FormatCSV Format = iota
+	// FormatJSONLines is one JSON object per line.
+	FormatJSONLines
+	// FormatColumnar is a compact binary format storing candles in column blocks.
+	// It is only supported for candles.
+	FormatColumnar
+)
+
+// CandleEncoder writes candles one at a time, so long histories never need to be held in memory.
+// Close must be called to flush buffered candles; it does not close the underlying writer.
+type CandleEncoder interface {
+	Encode(c Candle) error
+	Close() error
+}
+
+// CandleDecoder reads candles one at a time. Decode returns io.EOF after the last candle.
+type CandleDecoder interface {
+	Decode() (Candle, error)
+}
+
+// NewCandleEncoder returns an encoder writing candles to w in the given format.
+func NewCandleEncoder(w io.Writer, format Format) (CandleEncoder, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVEncoder(w, reflect.TypeOf(Candle{}))
+	case FormatJSONLines:
+		return &jsonLinesEncoder{w: bufio.NewWriter(w)}, nil
+	case FormatColumnar:
+		return newColumnarEncoder(w)
+	default:
+		return nil, fmt.Errorf("unsupported format %d", format)
+	}
+}
+
+// NewCandleDecoder returns a decoder reading candles from r in the given format.
+func NewCandleDecoder(r io.Reader, format Format) (CandleDecoder, error) {
+	switch format {
+	case FormatCSV:
+		d, err := newCSVDecoder(r, reflect.TypeOf(Candle{}))
+		if err != nil {
+			return nil, err
+		}
+		return candleDecoderFunc(func() (Candle, error) {
+			var c Candle
+			err := d.decode(&c)
+			return c, err
+		}), nil
+	case FormatJSONLines:
+		dec := json.NewDecoder(r)
+		return candleDecoderFunc(func() (Candle, error) {
+			var c Candle
+			err := dec.Decode(&c)
+			return c, err
+		}), nil
+	case FormatColumnar:
+		return newColumnarDecoder(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %d", format)
+	}
+}
+
+// WritePriceHistory writes the candles of a price history to w.
+func WritePriceHistory(w io.Writer, ph *PriceHistory, format Format) error {
+	enc, err := NewCandleEncoder(w, format)
+	if err != nil {
+		return err
+	}
+	for _, c := range ph.Candles {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
+
+// ReadPriceHistory reads every candle from r into a price history for symbol.
+func ReadPriceHistory(r io.Reader, symbol string, format Format) (*PriceHistory, error) {
+	dec, err := NewCandleDecoder(r, format)
+	if err != nil {
+		return nil, err
+	}
+	ph := &PriceHistory{Symbol: symbol}
+	for {
+		c, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		ph.Candles = append(ph.Candles, c)
+	}
+	ph.Empty = len(ph.Candles) == 0
+	return ph, nil
+}
+
+// WriteQuotes writes quotes to w ordered by symbol, in CSV or JSON Lines format.
+// CSV only holds the fields common to every asset type; JSON Lines keeps the complete quote.
+func WriteQuotes(w io.Writer, quotes Quotes, format Format) error {
+	symbols := make([]string, 0, len(quotes))
+	for symbol := range quotes {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	var enc interface {
+		encode(v interface{}) error
+		Close() error
+	}
+	switch format {
+	case FormatCSV:
+		e, err := newCSVEncoder(w, reflect.TypeOf(Quote{}))
+		if err != nil {
+			return err
+		}
+		enc = e
+	case FormatJSONLines:
+		enc = &jsonLinesEncoder{w: bufio.NewWriter(w)}
+	default:
+		return fmt.Errorf("unsupported format %d for quotes", format)
+	}
+
+	for _, symbol := range symbols {
+		if err := enc.encode(quotes[symbol]); err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
+
+// ReadQuotes reads quotes written by WriteQuotes, keyed by symbol.
+func ReadQuotes(r io.Reader, format Format) (Quotes, error) {
+	var decode func(q *Quote) error
+	switch format {
+	case FormatCSV:
+		d, err := newCSVDecoder(r, reflect.TypeOf(Quote{}))
+		if err != nil {
+			return nil, err
+		}
+		decode = func(q *Quote) error {
+			if err := d.decode(q); err != nil {
+				return err
+			}
+			// CSV only holds the common fields. Decode them again as JSON to fill in Data.
+			b, err := json.Marshal(q)
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(b, q)
+		}
+	case FormatJSONLines:
+		dec := json.NewDecoder(r)
+		decode = func(q *Quote) error { return dec.Decode(q) }
+	default:
+		return nil, fmt.Errorf("unsupported format %d for quotes", format)
+	}
+
+	quotes := Quotes{}
+	for {
+		q := new(Quote)
+		err := decode(q)
+		if err == io.EOF {
+			return quotes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		quotes[q.Symbol] = q
+	}
+}
+
+type candleDecoderFunc func() (Candle, error)
+
+func (f candleDecoderFunc) Decode() (Candle, error) { return f() }
+
+type jsonLinesEncoder struct {
+	w *bufio.Writer
+}
+
+func (e *jsonLinesEncoder) Encode(c Candle) error { return e.encode(c) }
+
+func (e *jsonLinesEncoder) encode(v interface{}) error {
+	// json.Encoder terminates every value with a newline.
+	return json.NewEncoder(e.w).Encode(v)
+}
+
+func (e *jsonLinesEncoder) Close() error { return e.w.Flush() }
+
+// csvEncoder writes flat structs as CSV, one column per field named after its JSON tag.
+type csvEncoder struct {
+	w      *csv.Writer
+	fields []int
+}
+
+func newCSVEncoder(w io.Writer, t reflect.Type) (*csvEncoder, error) {
+	names, fields := csvColumns(t)
+	e := &csvEncoder{w: csv.NewWriter(w), fields: fields}
+	return e, e.w.Write(names)
+}
+
+func (e *csvEncoder) Encode(c Candle) error { return e.encode(&c) }
+
+func (e *csvEncoder) encode(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	record := make([]string, len(e.fields))
+	for i, f := range e.fields {
+		field := rv.Field(f)
+		switch field.Kind() {
+		case reflect.String:
+			record[i] = field.String()
+		case reflect.Bool:
+			record[i] = strconv.FormatBool(field.Bool())
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			record[i] = strconv.FormatInt(field.Int(), 10)
+		case reflect.Float32, reflect.Float64:
+			record[i] = strconv.FormatFloat(field.Float(), 'f', -1, 64)
+		}
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type csvDecoder struct {
+	r      *csv.Reader
+	fields []int // struct field for each column, or -1 for unknown columns
+}
+
+func newCSVDecoder(r io.Reader, t reflect.Type) (*csvDecoder, error) {
+	d := &csvDecoder{r: csv.NewReader(r)}
+	header, err := d.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	names, fields := csvColumns(t)
+	byName := map[string]int{}
+	for i, name := range names {
+		byName[name] = fields[i]
+	}
+	for _, column := range header {
+		f, ok := byName[column]
+		if !ok {
+			f = -1
+		}
+		d.fields = append(d.fields, f)
+	}
+	return d, nil
+}
+
+func (d *csvDecoder) decode(v interface{}) error {
+	record, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v).Elem()
+	for i, f := range d.fields {
+		if f < 0 || i >= len(record) || record[i] == "" {
+			continue
+		}
+		field := rv.Field(f)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(record[i])
+		case reflect.Bool:
+			b, err := strconv.ParseBool(record[i])
+			if err != nil {
+				return err
+			}
+			field.SetBool(b)
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(record[i], 10, 64)
+			if err != nil {
+				return err
+			}
+			field.SetInt(n)
+		case reflect.Float32, reflect.Float64:
+			n, err := strconv.ParseFloat(record[i], 64)
+			if err != nil {
+				return err
+			}
+			field.SetFloat(n)
+		}
+	}
+	return nil
+}
+
+// csvColumns lists the JSON names and indexes of the scalar fields of a struct type.
+func csvColumns(t reflect.Type) ([]string, []int) {
+	var names []string
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+			names = append(names, name)
+			fields = append(fields, i)
+		}
+	}
+	return names, fields
+}
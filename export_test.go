@@ -0,0 +1,60 @@
+package tdameritrade
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCandleFormatsRoundTrip(t *testing.T) {
+	ph := &PriceHistory{Symbol: "SPY"}
+	for i := 0; i < columnarBlockSize+10; i++ {
+		ph.Candles = append(ph.Candles, Candle{
+			Datetime: 1610721000000 + i*60000,
+			Open:     370 + float64(i)/100,
+			High:     371.5,
+			Low:      369.25,
+			Close:    370.125,
+			Volume:   float64(1000 + i),
+		})
+	}
+
+	for _, format := range []Format{FormatCSV, FormatJSONLines, FormatColumnar} {
+		var buf bytes.Buffer
+		if err := WritePriceHistory(&buf, ph, format); err != nil {
+			t.Fatalf("format %d: %v", format, err)
+		}
+		got, err := ReadPriceHistory(&buf, "SPY", format)
+		if err != nil {
+			t.Fatalf("format %d: %v", format, err)
+		}
+		if !reflect.DeepEqual(got, ph) {
+			t.Errorf("format %d: candles did not round trip", format)
+		}
+	}
+}
+
+func TestQuotesFormatsRoundTrip(t *testing.T) {
+	quotes := Quotes{}
+	err := json.Unmarshal([]byte(`{
+		"SPY": {"symbol": "SPY", "assetType": "ETF", "bidPrice": 370.1, "askPrice": 370.2, "totalVolume": 1000000, "marginable": true},
+		"AAPL": {"symbol": "AAPL", "assetType": "EQUITY", "lastPrice": 127.14, "description": "Apple Inc. - Common Stock"}
+	}`), &quotes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []Format{FormatCSV, FormatJSONLines} {
+		var buf bytes.Buffer
+		if err := WriteQuotes(&buf, quotes, format); err != nil {
+			t.Fatalf("format %d: %v", format, err)
+		}
+		got, err := ReadQuotes(&buf, format)
+		if err != nil {
+			t.Fatalf("format %d: %v", format, err)
+		}
+		if !reflect.DeepEqual(got, quotes) {
+			t.Errorf("format %d: quotes did not round trip: %+v", format, got)
+		}
+	}
+}
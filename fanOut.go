@@ -0,0 +1,73 @@
+package tdameritrade
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// requestWorkers is how many requests the helpers that fan out over many calls make at once.
+const requestWorkers = 4
+
+// requestInterval spaces out request starts to stay under TD Ameritrade's limit of 120 requests a minute.
+var requestInterval = 500 * time.Millisecond
+
+// fanOut calls fn with every index below n from a pool of workers, starting calls no closer together
+// than interval, or as fast as the workers allow if interval is zero. The first error cancels the
+// context passed to the remaining calls, stops dispatching and is returned.
+func fanOut(ctx context.Context, n, workers int, interval time.Duration, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	jobs := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := fn(ctx, i); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+dispatch:
+	for i := 0; i < n; i++ {
+		if i > 0 && tick != nil {
+			select {
+			case <-ctx.Done():
+				break dispatch
+			case <-tick:
+			}
+		}
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,61 @@
+package tdameritrade
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFanOut(t *testing.T) {
+	var calls, running, peak int32
+	err := fanOut(context.Background(), 10, 3, 0, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&calls, 1)
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 10 {
+		t.Errorf("expected 10 calls, got %d", calls)
+	}
+	if peak > 3 {
+		t.Errorf("expected at most 3 calls at once, got %d", peak)
+	}
+
+	start := time.Now()
+	if err := fanOut(context.Background(), 3, 3, 20*time.Millisecond, func(ctx context.Context, i int) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected calls spaced by the interval, finished in %v", elapsed)
+	}
+
+	failed := errors.New("failed")
+	var uncancelled int32
+	err = fanOut(context.Background(), 100, 1, 0, func(ctx context.Context, i int) error {
+		if i == 2 {
+			return failed
+		}
+		if i > 2 && ctx.Err() == nil {
+			atomic.AddInt32(&uncancelled, 1)
+		}
+		return nil
+	})
+	if err != failed {
+		t.Errorf("expected the first error, got %v", err)
+	}
+	if uncancelled != 0 {
+		t.Errorf("expected calls after the error to see a cancelled context, got %d", uncancelled)
+	}
+}
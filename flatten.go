@@ -0,0 +1,231 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var (
+	// flattenPollInterval is how often FlattenAccount checks whether cancelled orders have finished.
+	flattenPollInterval = 500 * time.Millisecond
+	// flattenCancelTimeout bounds how long FlattenAccount waits for cancelled orders before giving up.
+	flattenCancelTimeout = 30 * time.Second
+)
+
+// terminalOrderStatuses are the statuses of orders that can no longer execute.
+var terminalOrderStatuses = []string{"CANCELED", "FILLED", "REJECTED", "EXPIRED", "REPLACED"}
+
+// BulkOrderResult is the outcome of a single cancel or closing order issued by
+// CancelAllOrders or FlattenAccount.
+type BulkOrderResult struct {
+	// OrderID is the ID of the cancelled order. It is zero for closing orders.
+	OrderID int64
+	// Symbol is the symbol of the cancelled order or closed position.
+	Symbol string
+	// Order is the closing order that was submitted. It is nil for cancels.
+	Order    *Order
+	Response *Response
+	Err      error
+}
+
+// ClosingOrder builds a market order that closes the given position.
+// Long positions are sold and short positions are bought back, using the
+// instruction matching the position's asset type.
+func ClosingOrder(position Position) (*Order, error) {
+	var instruction string
+	var quantity float64
+	long := position.LongQuantity > 0
+	switch {
+	case position.LongQuantity > 0:
+		quantity = position.LongQuantity
+	case position.ShortQuantity > 0:
+		quantity = position.ShortQuantity
+	default:
+		return nil, fmt.Errorf("position in %s has no quantity", InstrumentSymbol(position.Instrument))
+	}
+
+	switch position.Instrument.AssetType {
+	case "EQUITY":
+		instruction = "BUY_TO_COVER"
+		if long {
+			instruction = "SELL"
+		}
+	case "OPTION":
+		instruction = "BUY_TO_CLOSE"
+		if long {
+			instruction = "SELL_TO_CLOSE"
+		}
+	case "MUTUAL_FUND":
+		if !long {
+			return nil, fmt.Errorf("cannot close short mutual fund position in %s", InstrumentSymbol(position.Instrument))
+		}
+		instruction = "SELL"
+	default:
+		return nil, fmt.Errorf("cannot close position with asset type %s", position.Instrument.AssetType)
+	}
+
+	return &Order{
+		Session:           "NORMAL",
+		Duration:          "DAY",
+		OrderType:         "MARKET",
+		OrderStrategyType: "SINGLE",
+		OrderLegCollection: []*OrderLegCollection{
+			{
+				Instruction: instruction,
+				Quantity:    quantity,
+				Instrument:  position.Instrument,
+			},
+		},
+	}, nil
+}
+
+// CancelAllOrders cancels every cancelable order in an account, including the child orders of
+// conditional orders whose parent can no longer be cancelled, such as the working legs of a triggered OCO.
+// It returns one result per cancel request; a failed cancel does not stop the remaining ones.
+func (s *AccountsService) CancelAllOrders(ctx context.Context, accountID string) ([]BulkOrderResult, error) {
+	account, _, err := s.GetAccount(ctx, accountID, &AccountOptions{Position: true, Orders: true})
+	if err != nil {
+		return nil, err
+	}
+	return s.cancelOrders(ctx, accountID, account.OrderStrategies), nil
+}
+
+// FlattenAccount cancels every working order in an account and, if closePositions is set,
+// then submits a market order closing each open position.
+//
+// A cancel request only asks for the order to be cancelled, and a working order on the same shares
+// could still execute or hold them. So before closing anything FlattenAccount polls the account until
+// every order it tried to cancel has reached a terminal status, and then closes the positions as they
+// stand at that point. If a cancel request fails, or the orders are still working after a timeout,
+// no position is closed and an error is returned along with the cancel results.
+//
+// The returned results are in submission order and a failure of one request does not stop the remaining ones.
+func (s *AccountsService) FlattenAccount(ctx context.Context, accountID string, closePositions bool) ([]BulkOrderResult, error) {
+	account, _, err := s.GetAccount(ctx, accountID, &AccountOptions{Position: true, Orders: true})
+	if err != nil {
+		return nil, err
+	}
+
+	results := s.cancelOrders(ctx, accountID, account.OrderStrategies)
+	if !closePositions {
+		return results, nil
+	}
+
+	var cancelled, failed []int64
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result.OrderID)
+		} else {
+			cancelled = append(cancelled, result.OrderID)
+		}
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("cancelling orders %v failed, not closing positions", failed)
+	}
+	if len(cancelled) > 0 {
+		if account, err = s.waitForOrders(ctx, accountID, cancelled); err != nil {
+			return results, err
+		}
+	}
+
+	for _, position := range account.Positions {
+		result := BulkOrderResult{Symbol: InstrumentSymbol(position.Instrument)}
+		result.Order, result.Err = ClosingOrder(position)
+		if result.Err == nil {
+			result.Response, result.Err = s.PlaceOrder(ctx, accountID, result.Order)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// waitForOrders polls the account until none of the orders is working any more and returns the last account read.
+// Orders missing from the account are treated as finished.
+func (s *AccountsService) waitForOrders(ctx context.Context, accountID string, orderIDs []int64) (*Account, error) {
+	deadline := time.Now().Add(flattenCancelTimeout)
+	for {
+		account, _, err := s.GetAccount(ctx, accountID, &AccountOptions{Position: true, Orders: true})
+		if err != nil {
+			return nil, err
+		}
+
+		var working []int64
+		for _, order := range allOrders(account.OrderStrategies) {
+			for _, id := range orderIDs {
+				if order.OrderID == id && !contains(order.Status, terminalOrderStatuses) {
+					working = append(working, id)
+				}
+			}
+		}
+		if len(working) == 0 {
+			return account, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("orders %v still working %s after cancelling, not closing positions", working, flattenCancelTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(flattenPollInterval):
+		}
+	}
+}
+
+func (s *AccountsService) cancelOrders(ctx context.Context, accountID string, orders []OrderStrategy) []BulkOrderResult {
+	var results []BulkOrderResult
+	for _, order := range cancelableOrders(orders) {
+		result := BulkOrderResult{OrderID: order.OrderID}
+		if len(order.OrderLegCollection) > 0 {
+			result.Symbol = InstrumentSymbol(order.OrderLegCollection[0].Instrument)
+		}
+		result.Response, result.Err = s.CancelOrder(ctx, accountID, strconv.FormatInt(order.OrderID, 10))
+		results = append(results, result)
+	}
+	return results
+}
+
+// cancelableOrders returns the cancelable orders, descending into the children of orders that are not
+// themselves cancelable. Cancelling a parent cancels its children along with it.
+func cancelableOrders(orders []OrderStrategy) []OrderStrategy {
+	var cancelable []OrderStrategy
+	for _, order := range orders {
+		if order.Cancelable {
+			cancelable = append(cancelable, order)
+		} else {
+			cancelable = append(cancelable, cancelableOrders(order.ChildOrderStrategies)...)
+		}
+	}
+	return cancelable
+}
+
+// allOrders returns the orders and all of their child orders.
+func allOrders(orders []OrderStrategy) []OrderStrategy {
+	var all []OrderStrategy
+	for _, order := range orders {
+		all = append(all, order)
+		all = append(all, allOrders(order.ChildOrderStrategies)...)
+	}
+	return all
+}
+
+// InstrumentSymbol returns the symbol of an instrument regardless of its asset type.
+func InstrumentSymbol(i Instrument) string {
+	switch data := i.Data.(type) {
+	case *Equity:
+		return data.Symbol
+	case *OptionA:
+		return data.Symbol
+	case *MutualFund:
+		return data.Symbol
+	case *CashEquivalent:
+		return data.Symbol
+	case *FixedIncome:
+		return data.Symbol
+	default:
+		return ""
+	}
+}
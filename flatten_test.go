@@ -0,0 +1,197 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClosingOrder(t *testing.T) {
+	equity := Instrument{AssetType: "EQUITY", Data: &Equity{Symbol: "AAPL"}}
+	option := Instrument{AssetType: "OPTION", Data: &OptionA{Symbol: "AAPL_011521C130"}}
+	fund := Instrument{AssetType: "MUTUAL_FUND", Data: &MutualFund{Symbol: "VFIAX"}}
+	tests := []struct {
+		position    Position
+		instruction string
+		quantity    float64
+	}{
+		{Position{LongQuantity: 10, Instrument: equity}, "SELL", 10},
+		{Position{ShortQuantity: 5, Instrument: equity}, "BUY_TO_COVER", 5},
+		{Position{LongQuantity: 2, Instrument: option}, "SELL_TO_CLOSE", 2},
+		{Position{ShortQuantity: 3, Instrument: option}, "BUY_TO_CLOSE", 3},
+		{Position{LongQuantity: 1.5, Instrument: fund}, "SELL", 1.5},
+	}
+	for _, test := range tests {
+		order, err := ClosingOrder(test.position)
+		if err != nil {
+			t.Errorf("%s: %v", test.instruction, err)
+			continue
+		}
+		leg := order.OrderLegCollection[0]
+		if leg.Instruction != test.instruction || leg.Quantity != test.quantity || order.OrderType != "MARKET" {
+			t.Errorf("expected MARKET %s %v, got %s %s %v", test.instruction, test.quantity, order.OrderType, leg.Instruction, leg.Quantity)
+		}
+	}
+
+	for _, position := range []Position{
+		{ShortQuantity: 1, Instrument: fund},
+		{Instrument: equity},
+		{LongQuantity: 1, Instrument: Instrument{AssetType: "CURRENCY"}},
+	} {
+		if _, err := ClosingOrder(position); err == nil {
+			t.Errorf("expected an error closing %+v", position)
+		}
+	}
+}
+
+func TestFlattenAccountWaitsForCancels(t *testing.T) {
+	defer func(interval time.Duration) { flattenPollInterval = interval }(flattenPollInterval)
+	flattenPollInterval = time.Millisecond
+
+	var (
+		mu       sync.Mutex
+		status   = "WORKING"
+		reads    int
+		placed   []*Order
+		statuses []string
+	)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case "GET":
+			// The cancel takes two reads of the account to complete.
+			if status == "PENDING_CANCEL" {
+				if reads++; reads == 2 {
+					status = "CANCELED"
+				}
+			}
+			json.NewEncoder(w).Encode(Account{SecuritiesAccount{
+				Positions: []Position{{LongQuantity: 10, Instrument: Instrument{AssetType: "EQUITY", Data: &Equity{Symbol: "AAPL"}}}},
+				OrderStrategies: []OrderStrategy{
+					{OrderID: 1, Status: status, Cancelable: status == "WORKING"},
+					{OrderID: 2, Status: "FILLED"},
+				},
+			}})
+		case "DELETE":
+			if r.URL.Path != "/accounts/123/orders/1" {
+				t.Errorf("unexpected cancel %s", r.URL.Path)
+			}
+			status = "PENDING_CANCEL"
+		case "POST":
+			order := new(Order)
+			json.NewDecoder(r.Body).Decode(order)
+			placed = append(placed, order)
+			statuses = append(statuses, status)
+		}
+	})
+
+	results, err := c.Account.FlattenAccount(context.Background(), "123", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].OrderID != 1 || results[1].Symbol != "AAPL" {
+		t.Fatalf("expected a cancel followed by a close, got %+v", results)
+	}
+	if len(placed) != 1 || statuses[0] != "CANCELED" {
+		t.Errorf("expected the closing order only after the cancel completed, got %v", statuses)
+	}
+	if leg := placed[0].OrderLegCollection[0]; leg.Instruction != "SELL" || leg.Quantity != 10 {
+		t.Errorf("unexpected closing order %+v", leg)
+	}
+}
+
+func TestFlattenAccountTimesOut(t *testing.T) {
+	defer func(interval, timeout time.Duration) {
+		flattenPollInterval, flattenCancelTimeout = interval, timeout
+	}(flattenPollInterval, flattenCancelTimeout)
+	flattenPollInterval, flattenCancelTimeout = time.Millisecond, 10*time.Millisecond
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			t.Error("no closing order should be placed while an order is still working")
+		}
+		json.NewEncoder(w).Encode(Account{SecuritiesAccount{
+			Positions:       []Position{{LongQuantity: 10, Instrument: Instrument{AssetType: "EQUITY", Data: &Equity{Symbol: "AAPL"}}}},
+			OrderStrategies: []OrderStrategy{{OrderID: 1, Status: "WORKING", Cancelable: true}},
+		}})
+	})
+
+	results, err := c.Account.FlattenAccount(context.Background(), "123", true)
+	if err == nil {
+		t.Fatal("expected an error while the cancelled order keeps working")
+	}
+	if len(results) != 1 || results[0].OrderID != 1 {
+		t.Errorf("expected the cancel results to be returned, got %+v", results)
+	}
+}
+
+func TestFlattenAccountFailedCancel(t *testing.T) {
+	var reads int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			reads++
+			json.NewEncoder(w).Encode(Account{SecuritiesAccount{
+				Positions:       []Position{{LongQuantity: 10, Instrument: Instrument{AssetType: "EQUITY", Data: &Equity{Symbol: "AAPL"}}}},
+				OrderStrategies: []OrderStrategy{{OrderID: 1, Status: "WORKING", Cancelable: true}},
+			}})
+		case "DELETE":
+			http.Error(w, "order cannot be cancelled", http.StatusBadRequest)
+		case "POST":
+			t.Error("no closing order should be placed after a failed cancel")
+		}
+	})
+
+	results, err := c.Account.FlattenAccount(context.Background(), "123", true)
+	if err == nil {
+		t.Fatal("expected a failed cancel to be reported")
+	}
+	if len(results) != 1 || results[0].Err == nil {
+		t.Errorf("expected the failed cancel result, got %+v", results)
+	}
+	if reads != 1 {
+		t.Errorf("expected no waiting on an order that was not cancelled, got %d account reads", reads)
+	}
+}
+
+func TestCancelAllOrdersCancelsChildOrders(t *testing.T) {
+	var cancelled []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			cancelled = append(cancelled, r.URL.Path)
+			return
+		}
+		json.NewEncoder(w).Encode(Account{SecuritiesAccount{
+			OrderStrategies: []OrderStrategy{
+				// a triggered OCO: the parent has filled and both exits are working
+				{OrderID: 1, Status: "FILLED", ChildOrderStrategies: []OrderStrategy{
+					{OrderID: 2, Status: "WORKING", Cancelable: true},
+					{OrderID: 3, Status: "WORKING", Cancelable: true},
+				}},
+				// a working parent takes its child with it
+				{OrderID: 4, Status: "WORKING", Cancelable: true, ChildOrderStrategies: []OrderStrategy{
+					{OrderID: 5, Status: "AWAITING_PARENT_ORDER", Cancelable: true},
+				}},
+			},
+		}})
+	})
+
+	results, err := c.Account.CancelAllOrders(context.Background(), "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/accounts/123/orders/2", "/accounts/123/orders/3", "/accounts/123/orders/4"}
+	if len(results) != len(want) || len(cancelled) != len(want) {
+		t.Fatalf("expected cancels of %v, got %v", want, cancelled)
+	}
+	for i := range want {
+		if cancelled[i] != want[i] {
+			t.Errorf("expected cancels of %v, got %v", want, cancelled)
+			break
+		}
+	}
+}
@@ -3,6 +3,8 @@ package tdameritrade
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -14,6 +16,27 @@ type MarketHoursService struct {
 	client *Client
 }
 
+// Market is a market whose hours can be requested.
+type Market string
+
+const (
+	MarketEquity Market = "EQUITY"
+	MarketOption Market = "OPTION"
+	MarketFuture Market = "FUTURE"
+	MarketBond   Market = "BOND"
+	MarketForex  Market = "FOREX"
+)
+
+var validMarkets = []string{"EQUITY", "OPTION", "FUTURE", "BOND", "FOREX"}
+
+func (m Market) validate() error {
+	if !contains(strings.ToUpper(string(m)), validMarkets) {
+		return fmt.Errorf("invalid market %s, must have the value of one of the following %v", m, validMarkets)
+	}
+	return nil
+}
+
+// MarketHours holds the hours of each product, keyed by lower case market name and then product.
 type MarketHours map[string]map[string]*Hours
 
 type Period struct {
@@ -21,6 +44,28 @@ type Period struct {
 	End   string `json:"end"`
 }
 
+// Parse returns the start and end of the period.
+func (p Period) Parse() (time.Time, time.Time, error) {
+	start, err := time.Parse(time.RFC3339, p.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period start %q: %v", p.Start, err)
+	}
+	end, err := time.Parse(time.RFC3339, p.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period end %q: %v", p.End, err)
+	}
+	return start.In(MarketLocation), end.In(MarketLocation), nil
+}
+
+// Contains reports whether t falls within the period, including its start and excluding its end.
+func (p Period) Contains(t time.Time) (bool, error) {
+	start, end, err := p.Parse()
+	if err != nil {
+		return false, err
+	}
+	return !t.Before(start) && t.Before(end), nil
+}
+
 type SessionHours struct {
 	PreMarket     []Period `json:"preMarket"`
 	RegularMarket []Period `json:"regularMarket"`
@@ -38,12 +83,93 @@ type Hours struct {
 	SessionHours SessionHours `json:"sessionHours"`
 }
 
-func (s *MarketHoursService) GetMarketHoursMulti(ctx context.Context, markets string, date time.Time) (*MarketHours, *Response, error) {
+// Flatten returns the hours of every product, ordered by market and product.
+func (m MarketHours) Flatten() []*Hours {
+	markets := make([]string, 0, len(m))
+	for market := range m {
+		markets = append(markets, market)
+	}
+	sort.Strings(markets)
+
+	var hours []*Hours
+	for _, market := range markets {
+		products := make([]string, 0, len(m[market]))
+		for product := range m[market] {
+			products = append(products, product)
+		}
+		sort.Strings(products)
+		for _, product := range products {
+			if h := m[market][product]; h != nil {
+				hours = append(hours, h)
+			}
+		}
+	}
+	return hours
+}
+
+// ProductSession is one session of one product, with parsed times.
+type ProductSession struct {
+	Market  string
+	Product string
+	Date    string
+	Type    SessionType
+	Start   time.Time
+	End     time.Time
+}
+
+// Sessions returns the sessions of every open product, ordered by market and product
+// and then by start time.
+func (m MarketHours) Sessions() ([]ProductSession, error) {
+	var sessions []ProductSession
+	for _, h := range m.Flatten() {
+		if !h.IsOpen {
+			continue
+		}
+		var product []ProductSession
+		for _, group := range []struct {
+			typ     SessionType
+			periods []Period
+		}{
+			{SessionPre, h.SessionHours.PreMarket},
+			{SessionRegular, h.SessionHours.RegularMarket},
+			{SessionPost, h.SessionHours.PostMarket},
+		} {
+			for _, p := range group.periods {
+				start, end, err := p.Parse()
+				if err != nil {
+					return nil, err
+				}
+				product = append(product, ProductSession{
+					Market:  h.MarketType,
+					Product: h.Product,
+					Date:    h.Date,
+					Type:    group.typ,
+					Start:   start,
+					End:     end,
+				})
+			}
+		}
+		sort.SliceStable(product, func(i, j int) bool {
+			return product[i].Start.Before(product[j].Start)
+		})
+		sessions = append(sessions, product...)
+	}
+	return sessions, nil
+}
+
+func (s *MarketHoursService) GetMarketHoursMulti(ctx context.Context, date time.Time, markets ...Market) (*MarketHours, *Response, error) {
 	u := "marketdata/hours"
-	if markets == "" {
+	if len(markets) == 0 {
 		return nil, nil, fmt.Errorf("no markets present")
 	}
-	u = fmt.Sprintf("%s?markets=%s", u, markets)
+	names := make([]string, len(markets))
+	for i, m := range markets {
+		if err := m.validate(); err != nil {
+			return nil, nil, err
+		}
+		names[i] = strings.ToUpper(string(m))
+	}
+	u = fmt.Sprintf("%s?markets=%s", u, strings.Join(names, ","))
 	if !date.IsZero() {
 		u = fmt.Sprintf("%s&date=%s", u, date.Format("2006-01-02"))
 	}
@@ -64,7 +190,10 @@ func (s *MarketHoursService) GetMarketHoursMulti(ctx context.Context, markets st
 	return hours, resp, nil
 }
 
-func (s *MarketHoursService) GetMarketHours(ctx context.Context, market string, date time.Time) (*MarketHours, *Response, error) {
+func (s *MarketHoursService) GetMarketHours(ctx context.Context, market Market, date time.Time) (*MarketHours, *Response, error) {
+	if err := market.validate(); err != nil {
+		return nil, nil, err
+	}
 	u := fmt.Sprintf("marketdata/%s/hours", market)
 
 	if !date.IsZero() {
@@ -86,3 +215,26 @@ func (s *MarketHoursService) GetMarketHours(ctx context.Context, market string,
 
 	return hours, resp, nil
 }
+
+// GetMarketHoursRange fetches the hours of markets for every day from from through to,
+// fetching several days concurrently. The result holds one entry per day, in order.
+func (s *MarketHoursService) GetMarketHoursRange(ctx context.Context, from, to time.Time, markets ...Market) ([]*MarketHours, error) {
+	var days []time.Time
+	for d := startOfDay(from); !d.After(to); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	if len(days) == 0 {
+		return nil, fmt.Errorf("invalid date range, to must not be before from")
+	}
+
+	result := make([]*MarketHours, len(days))
+	err := fanOut(ctx, len(days), requestWorkers, requestInterval, func(ctx context.Context, i int) error {
+		hours, _, err := s.GetMarketHoursMulti(ctx, days[i], markets...)
+		result[i] = hours
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,142 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarketValidate(t *testing.T) {
+	for _, m := range []Market{MarketEquity, MarketForex, "option"} {
+		if err := m.validate(); err != nil {
+			t.Errorf("expected %s to be valid, got %v", m, err)
+		}
+	}
+	for _, m := range []Market{"", "CRYPTO"} {
+		if err := m.validate(); err == nil {
+			t.Errorf("expected %q to be rejected", m)
+		}
+	}
+}
+
+func TestGetMarketHoursMulti(t *testing.T) {
+	var requests []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery)
+		json.NewEncoder(w).Encode(MarketHours{"equity": {"EQ": {Product: "EQ", IsOpen: true}}})
+	})
+	ctx := context.Background()
+	date := time.Date(2021, 1, 15, 0, 0, 0, 0, MarketLocation)
+
+	hours, _, err := c.MarketHours.GetMarketHoursMulti(ctx, date, MarketEquity, "option")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/marketdata/hours?markets=EQUITY,OPTION&date=2021-01-15"; len(requests) != 1 || requests[0] != want {
+		t.Errorf("expected request %s, got %v", want, requests)
+	}
+	if h := (*hours)["equity"]["EQ"]; h == nil || !h.IsOpen {
+		t.Errorf("unexpected hours %+v", *hours)
+	}
+
+	if _, _, err := c.MarketHours.GetMarketHoursMulti(ctx, date); err == nil {
+		t.Error("expected no markets to be rejected")
+	}
+	if _, _, err := c.MarketHours.GetMarketHoursMulti(ctx, date, MarketEquity, "CRYPTO"); err == nil {
+		t.Error("expected an invalid market to be rejected")
+	}
+	if len(requests) != 1 {
+		t.Errorf("expected invalid calls to make no requests, got %v", requests)
+	}
+}
+
+func TestMarketHoursSessions(t *testing.T) {
+	at := func(hour, min int) string {
+		return time.Date(2021, 1, 15, hour, min, 0, 0, MarketLocation).Format(time.RFC3339)
+	}
+	hours := MarketHours{
+		"option": {
+			"IND": {MarketType: "OPTION", Product: "IND", IsOpen: true, SessionHours: SessionHours{
+				RegularMarket: []Period{{Start: at(9, 30), End: at(16, 15)}},
+			}},
+			"EQO": {MarketType: "OPTION", Product: "EQO", IsOpen: true, SessionHours: SessionHours{
+				RegularMarket: []Period{{Start: at(9, 30), End: at(16, 0)}},
+			}},
+		},
+		"equity": {
+			"EQ": {MarketType: "EQUITY", Product: "EQ", IsOpen: true, SessionHours: SessionHours{
+				PostMarket:    []Period{{Start: at(16, 0), End: at(20, 0)}},
+				RegularMarket: []Period{{Start: at(9, 30), End: at(16, 0)}},
+				PreMarket:     []Period{{Start: at(7, 0), End: at(9, 30)}},
+			}},
+		},
+		"bond": {"BON": {MarketType: "BOND", Product: "BON"}},
+	}
+
+	var products []string
+	for _, h := range hours.Flatten() {
+		products = append(products, h.Product)
+	}
+	if want := "BON EQ EQO IND"; strings.Join(products, " ") != want {
+		t.Errorf("expected products %s, got %v", want, products)
+	}
+
+	sessions, err := hours.Sessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, s := range sessions {
+		got = append(got, s.Product+":"+string(s.Type))
+	}
+	if want := "EQ:PRE EQ:REGULAR EQ:POST EQO:REGULAR IND:REGULAR"; strings.Join(got, " ") != want {
+		t.Errorf("expected sessions %s, got %v", want, got)
+	}
+	if end := sessions[len(sessions)-1].End; !end.Equal(time.Date(2021, 1, 15, 16, 15, 0, 0, MarketLocation)) {
+		t.Errorf("expected the index options to close at 16:15, got %v", end)
+	}
+
+	hours["equity"]["EQ"].SessionHours.PreMarket[0].Start = "7:00"
+	if _, err := hours.Sessions(); err == nil {
+		t.Error("expected an unparsable period to be rejected")
+	}
+}
+
+func TestGetMarketHoursRange(t *testing.T) {
+	interval := requestInterval
+	requestInterval = time.Millisecond
+	defer func() { requestInterval = interval }()
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		date := r.URL.Query().Get("date")
+		if date == "2021-01-20" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(MarketHours{"equity": {"EQ": {Product: "EQ", Date: date}}})
+	})
+	ctx := context.Background()
+	from := time.Date(2021, 1, 15, 12, 0, 0, 0, MarketLocation)
+
+	days, err := c.MarketHours.GetMarketHoursRange(ctx, from, from.AddDate(0, 0, 3), MarketEquity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dates []string
+	for _, d := range days {
+		dates = append(dates, (*d)["equity"]["EQ"].Date)
+	}
+	if want := "2021-01-15 2021-01-16 2021-01-17 2021-01-18"; strings.Join(dates, " ") != want {
+		t.Errorf("expected days %s in order, got %v", want, dates)
+	}
+
+	if _, err := c.MarketHours.GetMarketHoursRange(ctx, from, from.AddDate(0, 0, -1), MarketEquity); err == nil {
+		t.Error("expected a reversed range to be rejected")
+	}
+	if _, err := c.MarketHours.GetMarketHoursRange(ctx, from, from.AddDate(0, 0, 7), MarketEquity); err == nil {
+		t.Error("expected a failed day to fail the range")
+	}
+}
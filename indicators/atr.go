@@ -0,0 +1,46 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+// ATRStream is Wilder's average true range.
+type ATRStream struct {
+	period    int
+	count     int
+	prevClose float64
+	value     float64
+}
+
+// NewATRStream returns an average true range over period candles, typically 14.
+func NewATRStream(period int) *ATRStream {
+	return &ATRStream{period: period}
+}
+
+// Add adds a candle. It returns false until period candles have been added.
+func (a *ATRStream) Add(c tdameritrade.Candle) (float64, bool) {
+	if a.period <= 0 {
+		return 0, false
+	}
+	tr := c.High - c.Low
+	if a.count > 0 {
+		tr = math.Max(tr, math.Max(math.Abs(c.High-a.prevClose), math.Abs(c.Low-a.prevClose)))
+	}
+	a.prevClose = c.Close
+	a.count++
+
+	n := float64(a.period)
+	if a.count <= a.period {
+		a.value += tr / n
+		return a.value, a.count == a.period
+	}
+	a.value = (a.value*(n-1) + tr) / n
+	return a.value, true
+}
+
+// ATR returns Wilder's average true range over period candles.
+func ATR(candles []tdameritrade.Candle, period int) []float64 {
+	return batch(candles, NewATRStream(period).Add)
+}
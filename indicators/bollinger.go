@@ -0,0 +1,71 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+// BandValue is a single reading of Bollinger Bands.
+type BandValue struct {
+	Upper  float64
+	Middle float64
+	Lower  float64
+}
+
+// BollingerStream is Bollinger Bands around a simple moving average of closing prices.
+type BollingerStream struct {
+	period int
+	k      float64
+	window []float64
+	next   int
+	count  int
+}
+
+// NewBollingerStream returns Bollinger Bands over period candles, k population standard deviations
+// from the average. The usual settings are 20 and 2.
+func NewBollingerStream(period int, k float64) *BollingerStream {
+	return &BollingerStream{period: period, k: k, window: newWindow(period)}
+}
+
+// Add adds a candle's close. It returns false until period candles have been added.
+func (b *BollingerStream) Add(c tdameritrade.Candle) (BandValue, bool) {
+	if b.period <= 0 {
+		return BandValue{}, false
+	}
+	b.window[b.next] = c.Close
+	b.next = (b.next + 1) % b.period
+	if b.count < b.period {
+		b.count++
+	}
+	if b.count < b.period {
+		return BandValue{}, false
+	}
+
+	// Recomputing from the window avoids the drift of a running sum of squares.
+	var mean float64
+	for _, v := range b.window {
+		mean += v
+	}
+	mean /= float64(b.period)
+	var variance float64
+	for _, v := range b.window {
+		variance += (v - mean) * (v - mean)
+	}
+	sd := math.Sqrt(variance / float64(b.period))
+	return BandValue{Upper: mean + b.k*sd, Middle: mean, Lower: mean - b.k*sd}, true
+}
+
+// Bollinger returns Bollinger Bands of closing prices. Values without enough data are NaN in every field.
+func Bollinger(candles []tdameritrade.Candle, period int, k float64) []BandValue {
+	s := NewBollingerStream(period, k)
+	out := make([]BandValue, len(candles))
+	for i, c := range candles {
+		v, ok := s.Add(c)
+		if !ok {
+			v = BandValue{Upper: math.NaN(), Middle: math.NaN(), Lower: math.NaN()}
+		}
+		out[i] = v
+	}
+	return out
+}
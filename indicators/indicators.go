@@ -0,0 +1,35 @@
+// Package indicators computes technical indicators over price history candles.
+//
+// Every indicator comes in two forms. The batch form, such as SMA, takes a []tdameritrade.Candle
+// and returns one value per candle, with math.NaN() for candles before the indicator has enough data.
+// The streaming form, such as SMAStream, is fed one candle at a time with Add, which makes it
+// suitable for bars arriving from a live feed. Both produce identical values.
+// A stream with a period below one is never ready, so its batch form is all NaN.
+package indicators
+
+import (
+	"math"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+// newWindow returns the ring buffer of a stream over period values, or nil for a period below one.
+func newWindow(period int) []float64 {
+	if period <= 0 {
+		return nil
+	}
+	return make([]float64, period)
+}
+
+// batch runs add over every candle, recording NaN while it is not ready.
+func batch(candles []tdameritrade.Candle, add func(tdameritrade.Candle) (float64, bool)) []float64 {
+	out := make([]float64, len(candles))
+	for i, c := range candles {
+		v, ok := add(c)
+		if !ok {
+			v = math.NaN()
+		}
+		out[i] = v
+	}
+	return out
+}
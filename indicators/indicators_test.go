@@ -0,0 +1,135 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+func closes(values ...float64) []tdameritrade.Candle {
+	start := time.Date(2021, 1, 15, 9, 30, 0, 0, tdameritrade.MarketLocation)
+	candles := make([]tdameritrade.Candle, len(values))
+	for i, v := range values {
+		candles[i] = tdameritrade.Candle{
+			Datetime: int(tdameritrade.EpochMillis(start.Add(time.Duration(i) * time.Minute))),
+			Open:     v,
+			High:     v + 1,
+			Low:      v - 1,
+			Close:    v,
+			Volume:   100,
+		}
+	}
+	return candles
+}
+
+func assertValues(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %d values, got %d", name, len(want), len(got))
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) != math.IsNaN(got[i]) || !math.IsNaN(want[i]) && math.Abs(got[i]-want[i]) > 1e-4 {
+			t.Errorf("%s[%d]: expected %v, got %v", name, i, want[i], got[i])
+		}
+	}
+}
+
+func TestSMAAndEMA(t *testing.T) {
+	candles := closes(1, 2, 3, 4, 5, 6)
+	nan := math.NaN()
+	assertValues(t, "SMA", SMA(candles, 3), []float64{nan, nan, 2, 3, 4, 5})
+	assertValues(t, "EMA", EMA(candles, 3), []float64{nan, nan, 2, 3, 4, 5})
+
+	candles = closes(10, 10, 10, 20)
+	assertValues(t, "EMA", EMA(candles, 3), []float64{nan, nan, 10, 15})
+}
+
+func TestRSI(t *testing.T) {
+	// Closing prices from the StockCharts RSI example. StockCharts rounds its intermediate averages,
+	// so its published 70.53 and 66.32 differ slightly from the exact values.
+	candles := closes(44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08, 45.89, 46.03, 45.61, 46.28, 46.28, 46.00)
+	rsi := RSI(candles, 14)
+	for i := 0; i < 14; i++ {
+		if !math.IsNaN(rsi[i]) {
+			t.Errorf("RSI[%d]: expected NaN during warm up, got %v", i, rsi[i])
+		}
+	}
+	assertValues(t, "RSI", rsi[14:], []float64{70.4641, 66.2496})
+
+	if v := RSI(closes(1, 2, 3), 2)[2]; v != 100 {
+		t.Errorf("RSI of only gains: expected 100, got %v", v)
+	}
+}
+
+func TestMACD(t *testing.T) {
+	values := make([]float64, 40)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	macd := MACD(closes(values...), 12, 26, 9)
+	if !math.IsNaN(macd[32].MACD) {
+		t.Errorf("expected MACD to warm up until the signal line is ready, got %+v", macd[32])
+	}
+	// For a linear series both EMAs lag by (period-1)/2, so MACD settles at (26-12)/2.
+	last := macd[len(macd)-1]
+	if math.Abs(last.MACD-7) > 1e-9 || math.Abs(last.Signal-7) > 1e-9 || math.Abs(last.Histogram) > 1e-9 {
+		t.Errorf("unexpected MACD %+v", last)
+	}
+}
+
+func TestBollinger(t *testing.T) {
+	bands := Bollinger(closes(2, 4, 4, 4, 5, 5, 7, 9), 8, 2)
+	last := bands[7]
+	if last.Middle != 5 || last.Upper != 9 || last.Lower != 1 {
+		t.Errorf("unexpected bands %+v", last)
+	}
+	if !math.IsNaN(bands[6].Middle) {
+		t.Errorf("expected NaN during warm up, got %+v", bands[6])
+	}
+}
+
+func TestATR(t *testing.T) {
+	candles := closes(10, 10, 14)
+	// True ranges are 2, 2 and 5 (from the previous close of 10 to the high of 15).
+	assertValues(t, "ATR", ATR(candles, 2), []float64{math.NaN(), 2, 3.5})
+}
+
+func TestVWAPResetsEachDay(t *testing.T) {
+	candles := closes(10, 20)
+	candles[1].Volume = 300
+	next := closes(50)
+	next[0].Datetime += int((24 * time.Hour) / time.Millisecond)
+	candles = append(candles, next...)
+	assertValues(t, "VWAP", VWAP(candles), []float64{10, 17.5, 50})
+}
+
+func TestStreamingMatchesBatch(t *testing.T) {
+	candles := closes(5, 7, 6, 8, 9, 7, 6, 8, 10, 11, 9, 12)
+	batch := RSI(candles, 4)
+	stream := NewRSIStream(4)
+	for i, c := range candles {
+		v, ok := stream.Add(c)
+		if ok == math.IsNaN(batch[i]) || ok && v != batch[i] {
+			t.Errorf("RSI[%d]: stream %v (%v), batch %v", i, v, ok, batch[i])
+		}
+	}
+}
+
+func TestInvalidPeriods(t *testing.T) {
+	candles := closes(1, 2, 3)
+	nan := []float64{math.NaN(), math.NaN(), math.NaN()}
+	for _, period := range []int{0, -1} {
+		assertValues(t, "SMA", SMA(candles, period), nan)
+		assertValues(t, "EMA", EMA(candles, period), nan)
+		assertValues(t, "RSI", RSI(candles, period), nan)
+		assertValues(t, "ATR", ATR(candles, period), nan)
+		stream := NewBollingerStream(period, 2)
+		for _, c := range candles {
+			if _, ok := stream.Add(c); ok {
+				t.Errorf("expected Bollinger Bands over period %d never to be ready", period)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+// MACDValue is a single reading of the MACD indicator.
+type MACDValue struct {
+	MACD      float64
+	Signal    float64
+	Histogram float64
+}
+
+// MACDStream is the moving average convergence divergence of closing prices.
+type MACDStream struct {
+	fast   *EMAStream
+	slow   *EMAStream
+	signal *EMAStream
+}
+
+// NewMACDStream returns a MACD with the given fast, slow and signal periods, typically 12, 26 and 9.
+func NewMACDStream(fast, slow, signal int) *MACDStream {
+	return &MACDStream{
+		fast:   NewEMAStream(fast),
+		slow:   NewEMAStream(slow),
+		signal: NewEMAStream(signal),
+	}
+}
+
+// Add adds a candle. It returns false until the signal line has enough data.
+func (m *MACDStream) Add(c tdameritrade.Candle) (MACDValue, bool) {
+	fast, fastOK := m.fast.Add(c)
+	slow, slowOK := m.slow.Add(c)
+	if !fastOK || !slowOK {
+		return MACDValue{}, false
+	}
+	macd := fast - slow
+	signal, ok := m.signal.Update(macd)
+	if !ok {
+		return MACDValue{}, false
+	}
+	return MACDValue{MACD: macd, Signal: signal, Histogram: macd - signal}, true
+}
+
+// MACD returns the MACD of closing prices. Values without enough data are NaN in every field.
+func MACD(candles []tdameritrade.Candle, fast, slow, signal int) []MACDValue {
+	m := NewMACDStream(fast, slow, signal)
+	out := make([]MACDValue, len(candles))
+	for i, c := range candles {
+		v, ok := m.Add(c)
+		if !ok {
+			v = MACDValue{MACD: math.NaN(), Signal: math.NaN(), Histogram: math.NaN()}
+		}
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package indicators
+
+import "github.com/zricethezav/go-tdameritrade"
+
+// SMAStream is a simple moving average of closing prices.
+type SMAStream struct {
+	period int
+	window []float64
+	next   int
+	count  int
+	sum    float64
+}
+
+// NewSMAStream returns a simple moving average over period candles.
+func NewSMAStream(period int) *SMAStream {
+	return &SMAStream{period: period, window: newWindow(period)}
+}
+
+// Add adds a candle's close. It returns false until period candles have been added.
+func (s *SMAStream) Add(c tdameritrade.Candle) (float64, bool) {
+	return s.Update(c.Close)
+}
+
+// Update adds a value. It returns false until period values have been added.
+func (s *SMAStream) Update(v float64) (float64, bool) {
+	if s.period <= 0 {
+		return 0, false
+	}
+	s.sum += v - s.window[s.next]
+	s.window[s.next] = v
+	s.next = (s.next + 1) % s.period
+	if s.count < s.period {
+		s.count++
+	}
+	if s.count < s.period {
+		return 0, false
+	}
+	return s.sum / float64(s.period), true
+}
+
+// SMA returns the simple moving average of closing prices over period candles.
+func SMA(candles []tdameritrade.Candle, period int) []float64 {
+	return batch(candles, NewSMAStream(period).Add)
+}
+
+// EMAStream is an exponential moving average of closing prices.
+// It is seeded with the simple average of the first period values.
+type EMAStream struct {
+	alpha float64
+	seed  *SMAStream
+	value float64
+	ready bool
+}
+
+// NewEMAStream returns an exponential moving average over period candles, with a smoothing
+// factor of 2/(period+1).
+func NewEMAStream(period int) *EMAStream {
+	return &EMAStream{alpha: 2 / float64(period+1), seed: NewSMAStream(period)}
+}
+
+// Add adds a candle's close. It returns false until period candles have been added.
+func (e *EMAStream) Add(c tdameritrade.Candle) (float64, bool) {
+	return e.Update(c.Close)
+}
+
+// Update adds a value. It returns false until period values have been added.
+func (e *EMAStream) Update(v float64) (float64, bool) {
+	if !e.ready {
+		e.value, e.ready = e.seed.Update(v)
+		return e.value, e.ready
+	}
+	e.value += e.alpha * (v - e.value)
+	return e.value, true
+}
+
+// EMA returns the exponential moving average of closing prices over period candles.
+func EMA(candles []tdameritrade.Candle, period int) []float64 {
+	return batch(candles, NewEMAStream(period).Add)
+}
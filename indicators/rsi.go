@@ -0,0 +1,67 @@
+package indicators
+
+import "github.com/zricethezav/go-tdameritrade"
+
+// RSIStream is Wilder's relative strength index of closing prices.
+type RSIStream struct {
+	period  int
+	prev    float64
+	count   int
+	avgGain float64
+	avgLoss float64
+}
+
+// NewRSIStream returns a relative strength index over period changes, typically 14.
+func NewRSIStream(period int) *RSIStream {
+	return &RSIStream{period: period}
+}
+
+// Add adds a candle's close. It returns false until period+1 candles have been added.
+func (r *RSIStream) Add(c tdameritrade.Candle) (float64, bool) {
+	return r.Update(c.Close)
+}
+
+// Update adds a value. It returns false until period+1 values have been added.
+func (r *RSIStream) Update(v float64) (float64, bool) {
+	if r.period <= 0 {
+		return 0, false
+	}
+	r.count++
+	if r.count == 1 {
+		r.prev = v
+		return 0, false
+	}
+	change := v - r.prev
+	r.prev = v
+	var gain, loss float64
+	if change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+
+	n := float64(r.period)
+	if r.count <= r.period+1 {
+		r.avgGain += gain / n
+		r.avgLoss += loss / n
+		if r.count <= r.period {
+			return 0, false
+		}
+	} else {
+		r.avgGain = (r.avgGain*(n-1) + gain) / n
+		r.avgLoss = (r.avgLoss*(n-1) + loss) / n
+	}
+
+	if r.avgLoss == 0 {
+		if r.avgGain == 0 {
+			return 50, true
+		}
+		return 100, true
+	}
+	return 100 - 100/(1+r.avgGain/r.avgLoss), true
+}
+
+// RSI returns Wilder's relative strength index of closing prices over period changes.
+func RSI(candles []tdameritrade.Candle, period int) []float64 {
+	return batch(candles, NewRSIStream(period).Add)
+}
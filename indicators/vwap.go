@@ -0,0 +1,40 @@
+package indicators
+
+import (
+	"time"
+
+	"github.com/zricethezav/go-tdameritrade"
+)
+
+// VWAPStream is the volume weighted average of each candle's typical price, (high+low+close)/3.
+// It resets at the start of every New York trading day.
+type VWAPStream struct {
+	day      time.Time
+	volume   float64
+	priceVol float64
+}
+
+// NewVWAPStream returns a session VWAP.
+func NewVWAPStream() *VWAPStream {
+	return &VWAPStream{}
+}
+
+// Add adds a candle. It returns false until the session has traded some volume.
+func (v *VWAPStream) Add(c tdameritrade.Candle) (float64, bool) {
+	t := c.Time()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if !day.Equal(v.day) {
+		v.day, v.volume, v.priceVol = day, 0, 0
+	}
+	v.volume += c.Volume
+	v.priceVol += (c.High + c.Low + c.Close) / 3 * c.Volume
+	if v.volume == 0 {
+		return 0, false
+	}
+	return v.priceVol / v.volume, true
+}
+
+// VWAP returns the session VWAP at each candle.
+func VWAP(candles []tdameritrade.Candle) []float64 {
+	return batch(candles, NewVWAPStream().Add)
+}
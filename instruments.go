@@ -3,8 +3,22 @@ package tdameritrade
 import (
 	"context"
 	"fmt"
+	"net/url"
 )
 
+// Projection is the kind of search SearchInstruments performs.
+type Projection string
+
+const (
+	ProjectionSymbolSearch Projection = "symbol-search"
+	ProjectionSymbolRegex  Projection = "symbol-regex"
+	ProjectionDescSearch   Projection = "desc-search"
+	ProjectionDescRegex    Projection = "desc-regex"
+	ProjectionFundamental  Projection = "fundamental"
+)
+
+var validProjections = []string{"symbol-search", "symbol-regex", "desc-search", "desc-regex", "fundamental"}
+
 // InstrumentService handles communication with the marketdata related methods of
 // the TDAmeritrade API.
 //
@@ -21,37 +35,101 @@ type InstrumentInfo struct {
 	Description string `json:"description,omitempty"`
 	Type        string `json:"assetType"` //"'NOT_APPLICABLE' or 'OPEN_END_NON_TAXABLE' or 'OPEN_END_TAXABLE' or 'NO_LOAD_NON_TAXABLE' or 'NO_LOAD_TAXABLE'"
 	Exchange    string `json:"exchange"`
+	// Fundamental is only returned by the fundamental projection.
+	Fundamental *Fundamental `json:"fundamental,omitempty"`
+}
+
+// Fundamental is the fundamental data of an instrument. Ratios, margins and yields are percentages.
+type Fundamental struct {
+	Symbol              string  `json:"symbol"`
+	High52              float64 `json:"high52"`
+	Low52               float64 `json:"low52"`
+	DividendAmount      float64 `json:"dividendAmount"`
+	DividendYield       float64 `json:"dividendYield"`
+	DividendDate        string  `json:"dividendDate"`
+	PeRatio             float64 `json:"peRatio"`
+	PegRatio            float64 `json:"pegRatio"`
+	PbRatio             float64 `json:"pbRatio"`
+	PrRatio             float64 `json:"prRatio"`
+	PcfRatio            float64 `json:"pcfRatio"`
+	GrossMarginTTM      float64 `json:"grossMarginTTM"`
+	GrossMarginMRQ      float64 `json:"grossMarginMRQ"`
+	NetProfitMarginTTM  float64 `json:"netProfitMarginTTM"`
+	NetProfitMarginMRQ  float64 `json:"netProfitMarginMRQ"`
+	OperatingMarginTTM  float64 `json:"operatingMarginTTM"`
+	OperatingMarginMRQ  float64 `json:"operatingMarginMRQ"`
+	ReturnOnEquity      float64 `json:"returnOnEquity"`
+	ReturnOnAssets      float64 `json:"returnOnAssets"`
+	ReturnOnInvestment  float64 `json:"returnOnInvestment"`
+	QuickRatio          float64 `json:"quickRatio"`
+	CurrentRatio        float64 `json:"currentRatio"`
+	InterestCoverage    float64 `json:"interestCoverage"`
+	TotalDebtToCapital  float64 `json:"totalDebtToCapital"`
+	LtDebtToEquity      float64 `json:"ltDebtToEquity"`
+	TotalDebtToEquity   float64 `json:"totalDebtToEquity"`
+	EpsTTM              float64 `json:"epsTTM"`
+	EpsChangePercentTTM float64 `json:"epsChangePercentTTM"`
+	EpsChangeYear       float64 `json:"epsChangeYear"`
+	EpsChange           float64 `json:"epsChange"`
+	RevChangeYear       float64 `json:"revChangeYear"`
+	RevChangeTTM        float64 `json:"revChangeTTM"`
+	RevChangeIn         float64 `json:"revChangeIn"`
+	SharesOutstanding   float64 `json:"sharesOutstanding"`
+	MarketCapFloat      float64 `json:"marketCapFloat"`
+	// MarketCap is in millions of dollars.
+	MarketCap          float64 `json:"marketCap"`
+	BookValuePerShare  float64 `json:"bookValuePerShare"`
+	ShortIntToFloat    float64 `json:"shortIntToFloat"`
+	ShortIntDayToCover float64 `json:"shortIntDayToCover"`
+	DivGrowthRate3Year float64 `json:"divGrowthRate3Year"`
+	DividendPayAmount  float64 `json:"dividendPayAmount"`
+	DividendPayDate    string  `json:"dividendPayDate"`
+	Beta               float64 `json:"beta"`
+	Vol1DayAvg         float64 `json:"vol1DayAvg"`
+	Vol10DayAvg        float64 `json:"vol10DayAvg"`
+	Vol3MonthAvg       float64 `json:"vol3MonthAvg"`
 }
 
+// GetInstrument looks up the instruments with a CUSIP, keyed by symbol.
 func (s *InstrumentService) GetInstrument(ctx context.Context, cusip string) (*Instruments, *Response, error) {
 	if cusip == "" {
 		return nil, nil, fmt.Errorf("no cusip present")
 	}
-	u := fmt.Sprintf("instruments/%s", cusip)
+	u := fmt.Sprintf("instruments/%s", url.PathEscape(cusip))
 	req, err := s.client.NewRequest("GET", u, nil)
 
 	if err != nil {
 		return nil, nil, err
 	}
 
-	instruments := new(Instruments)
+	// this endpoint returns a list rather than the map returned by a search
+	var list []*InstrumentInfo
 
-	resp, err := s.client.Do(ctx, req, instruments)
+	resp, err := s.client.Do(ctx, req, &list)
 	if err != nil {
 		return nil, resp, err
 	}
-	return nil, nil, nil
+
+	instruments := Instruments{}
+	for _, info := range list {
+		instruments[info.Symbol] = info
+	}
+	return &instruments, resp, nil
 }
 
-func (s *InstrumentService) SearchInstruments(ctx context.Context, symbol, projection string) (*Instruments, *Response, error) {
+func (s *InstrumentService) SearchInstruments(ctx context.Context, symbol string, projection Projection) (*Instruments, *Response, error) {
 	u := "instruments"
 	if symbol == "" {
 		return nil, nil, fmt.Errorf("no symbol present")
 	}
 	if projection == "" {
-		projection = "symbol-search"
+		projection = ProjectionSymbolSearch
+	}
+	if !contains(string(projection), validProjections) {
+		return nil, nil, fmt.Errorf("invalid projection, must have the value of one of the following %v", validProjections)
 	}
-	u = fmt.Sprintf("%s?symbol=%s&projection=%s", u, symbol, projection)
+	q := url.Values{"symbol": {symbol}, "projection": {string(projection)}}
+	u = fmt.Sprintf("%s?%s", u, q.Encode())
 
 	req, err := s.client.NewRequest("GET", u, nil)
 
@@ -0,0 +1,233 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCalendarSearchDays bounds how far NextOpen and NextClose look ahead.
+const maxCalendarSearchDays = 14
+
+// HoursSource returns the market hours of a market for a day.
+type HoursSource interface {
+	Hours(ctx context.Context, market Market, date time.Time) (*MarketHours, error)
+}
+
+type serviceHoursSource struct {
+	s *MarketHoursService
+}
+
+func (src serviceHoursSource) Hours(ctx context.Context, market Market, date time.Time) (*MarketHours, error) {
+	hours, _, err := src.s.GetMarketHours(ctx, market, date)
+	return hours, err
+}
+
+// Session is a trading session with parsed start and end times.
+type Session struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains reports whether t falls within the session, including its start and excluding its end.
+func (s Session) Contains(t time.Time) bool {
+	return !t.Before(s.Start) && t.Before(s.End)
+}
+
+// SessionType is the part of a trading day a time falls in.
+type SessionType string
+
+const (
+	SessionClosed  SessionType = ""
+	SessionPre     SessionType = "PRE"
+	SessionRegular SessionType = "REGULAR"
+	SessionPost    SessionType = "POST"
+)
+
+// TradingDay is the parsed market hours of one day.
+type TradingDay struct {
+	// Date is midnight New York time.
+	Date          time.Time
+	IsOpen        bool
+	PreMarket     []Session
+	RegularMarket []Session
+	PostMarket    []Session
+}
+
+// Session returns the session containing t.
+func (d *TradingDay) Session(t time.Time) (SessionType, Session) {
+	for _, s := range d.RegularMarket {
+		if s.Contains(t) {
+			return SessionRegular, s
+		}
+	}
+	for _, s := range d.PreMarket {
+		if s.Contains(t) {
+			return SessionPre, s
+		}
+	}
+	for _, s := range d.PostMarket {
+		if s.Contains(t) {
+			return SessionPost, s
+		}
+	}
+	return SessionClosed, Session{}
+}
+
+// MarketCalendar answers questions about the trading sessions of a market,
+// caching the hours of each day it looks up.
+type MarketCalendar struct {
+	Market Market
+	// Product restricts the calendar to one product, such as "EQ" or "/ES".
+	// When empty the sessions of every product in the market are combined.
+	Product string
+
+	source HoursSource
+	mu     sync.Mutex
+	days   map[string]*TradingDay
+}
+
+// NewMarketCalendar returns a calendar for market that looks up hours with the client's MarketHoursService.
+func NewMarketCalendar(client *Client, market Market) *MarketCalendar {
+	return NewMarketCalendarWithSource(serviceHoursSource{client.MarketHours}, market)
+}
+
+// NewMarketCalendarWithSource returns a calendar for market that looks up hours from source.
+func NewMarketCalendarWithSource(source HoursSource, market Market) *MarketCalendar {
+	return &MarketCalendar{
+		Market: Market(strings.ToUpper(string(market))),
+		source: source,
+		days:   map[string]*TradingDay{},
+	}
+}
+
+// Day returns the trading day containing t.
+func (c *MarketCalendar) Day(ctx context.Context, t time.Time) (*TradingDay, error) {
+	if err := c.Market.validate(); err != nil {
+		return nil, err
+	}
+	date := startOfDay(t)
+	key := date.Format("2006-01-02")
+
+	c.mu.Lock()
+	day, ok := c.days[key]
+	c.mu.Unlock()
+	if ok {
+		return day, nil
+	}
+
+	hours, err := c.source.Hours(ctx, c.Market, date)
+	if err != nil {
+		return nil, err
+	}
+	day, err = newTradingDay(date, hours, c.Product)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.days[key] = day
+	c.mu.Unlock()
+	return day, nil
+}
+
+// newTradingDay combines the sessions of the products in hours, or only those of product when it is set.
+func newTradingDay(date time.Time, hours *MarketHours, product string) (*TradingDay, error) {
+	day := &TradingDay{Date: date}
+	if hours == nil {
+		return day, nil
+	}
+	for _, products := range *hours {
+		for key, h := range products {
+			if h == nil || !h.IsOpen || product != "" && key != product && h.Product != product {
+				continue
+			}
+			day.IsOpen = true
+			for _, sessions := range []struct {
+				periods []Period
+				dst     *[]Session
+			}{
+				{h.SessionHours.PreMarket, &day.PreMarket},
+				{h.SessionHours.RegularMarket, &day.RegularMarket},
+				{h.SessionHours.PostMarket, &day.PostMarket},
+			} {
+				for _, p := range sessions.periods {
+					start, end, err := p.Parse()
+					if err != nil {
+						return nil, err
+					}
+					*sessions.dst = append(*sessions.dst, Session{Start: start, End: end})
+				}
+			}
+		}
+	}
+	return day, nil
+}
+
+// IsOpen reports whether t falls within a regular session.
+func (c *MarketCalendar) IsOpen(ctx context.Context, t time.Time) (bool, error) {
+	session, err := c.Session(ctx, t)
+	return session == SessionRegular, err
+}
+
+// Session returns the session t falls in, or SessionClosed.
+func (c *MarketCalendar) Session(ctx context.Context, t time.Time) (SessionType, error) {
+	// Sessions of markets trading overnight can start on the previous day.
+	for _, d := range []time.Time{t, t.AddDate(0, 0, -1)} {
+		day, err := c.Day(ctx, d)
+		if err != nil {
+			return SessionClosed, err
+		}
+		if session, _ := day.Session(t); session != SessionClosed {
+			return session, nil
+		}
+	}
+	return SessionClosed, nil
+}
+
+// NextOpen returns the start of the first regular session after t.
+func (c *MarketCalendar) NextOpen(ctx context.Context, t time.Time) (time.Time, error) {
+	return c.next(ctx, t, func(s Session) time.Time { return s.Start })
+}
+
+// NextClose returns the end of the first regular session ending after t.
+func (c *MarketCalendar) NextClose(ctx context.Context, t time.Time) (time.Time, error) {
+	return c.next(ctx, t, func(s Session) time.Time { return s.End })
+}
+
+func (c *MarketCalendar) next(ctx context.Context, t time.Time, edge func(Session) time.Time) (time.Time, error) {
+	var next time.Time
+	for i := -1; i <= maxCalendarSearchDays; i++ {
+		day, err := c.Day(ctx, t.AddDate(0, 0, i))
+		if err != nil {
+			return time.Time{}, err
+		}
+		for _, s := range day.RegularMarket {
+			if e := edge(s); e.After(t) && (next.IsZero() || e.Before(next)) {
+				next = e
+			}
+		}
+		// Later days cannot hold an earlier edge once one is found past the previous day.
+		if !next.IsZero() && i >= 0 {
+			return next, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no %s session within %d days of %s", c.Market, maxCalendarSearchDays, t.Format(time.RFC3339))
+}
+
+// TradingDaysBetween returns the dates from a through b, inclusive, on which the market is open.
+func (c *MarketCalendar) TradingDaysBetween(ctx context.Context, a, b time.Time) ([]time.Time, error) {
+	var days []time.Time
+	for d := startOfDay(a); !d.After(b); d = d.AddDate(0, 0, 1) {
+		day, err := c.Day(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		if day.IsOpen {
+			days = append(days, day.Date)
+		}
+	}
+	return days, nil
+}
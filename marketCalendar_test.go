@@ -0,0 +1,75 @@
+package tdameritrade
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// weekdayHours opens Monday to Friday except on the listed holidays, 4:00-9:30 pre, 9:30-16:00 regular, 16:00-20:00 post.
+type weekdayHours struct {
+	holidays map[string]bool
+	calls    int
+}
+
+func (w *weekdayHours) Hours(ctx context.Context, market Market, date time.Time) (*MarketHours, error) {
+	w.calls++
+	h := &Hours{Date: date.Format("2006-01-02"), Product: "EQ"}
+	if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday && !w.holidays[h.Date] {
+		at := func(hour, min int) string {
+			return time.Date(date.Year(), date.Month(), date.Day(), hour, min, 0, 0, MarketLocation).Format(time.RFC3339)
+		}
+		h.IsOpen = true
+		h.SessionHours = SessionHours{
+			PreMarket:     []Period{{Start: at(4, 0), End: at(9, 30)}},
+			RegularMarket: []Period{{Start: at(9, 30), End: at(16, 0)}},
+			PostMarket:    []Period{{Start: at(16, 0), End: at(20, 0)}},
+		}
+	}
+	return &MarketHours{"equity": {"EQ": h}}, nil
+}
+
+func TestMarketCalendar(t *testing.T) {
+	ctx := context.Background()
+	source := &weekdayHours{holidays: map[string]bool{"2021-01-18": true}}
+	cal := NewMarketCalendarWithSource(source, "equity")
+
+	friday := time.Date(2021, 1, 15, 10, 0, 0, 0, MarketLocation)
+	if open, err := cal.IsOpen(ctx, friday); err != nil || !open {
+		t.Errorf("expected open on Friday morning, got %v %v", open, err)
+	}
+	if session, _ := cal.Session(ctx, friday.Add(7*time.Hour)); session != SessionPost {
+		t.Errorf("expected post market session, got %q", session)
+	}
+
+	next, err := cal.NextOpen(ctx, friday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2021, 1, 19, 9, 30, 0, 0, MarketLocation); !next.Equal(want) {
+		t.Errorf("expected next open %v after the holiday weekend, got %v", want, next)
+	}
+	nextClose, err := cal.NextClose(ctx, friday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2021, 1, 15, 16, 0, 0, 0, MarketLocation); !nextClose.Equal(want) {
+		t.Errorf("expected next close %v, got %v", want, nextClose)
+	}
+
+	days, err := cal.TradingDaysBetween(ctx, friday, friday.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 5 {
+		t.Errorf("expected 5 trading days, got %v", days)
+	}
+
+	calls := source.calls
+	if _, err := cal.IsOpen(ctx, friday.AddDate(0, 0, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if source.calls != calls {
+		t.Errorf("expected cached days to be reused, got %d more calls", source.calls-calls)
+	}
+}
@@ -0,0 +1,275 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The offline rules cover NYSE and CME holidays from offlineRulesStart onwards.
+var offlineRulesStart = time.Date(2000, 1, 1, 0, 0, 0, 0, MarketLocation)
+
+// nyseSpecialClosures are unscheduled NYSE closures that no rule predicts.
+var nyseSpecialClosures = map[string]string{
+	"2001-09-11": "September 11 attacks",
+	"2001-09-12": "September 11 attacks",
+	"2001-09-13": "September 11 attacks",
+	"2001-09-14": "September 11 attacks",
+	"2004-06-11": "National Day of Mourning for Ronald Reagan",
+	"2007-01-02": "National Day of Mourning for Gerald Ford",
+	"2012-10-29": "Hurricane Sandy",
+	"2012-10-30": "Hurricane Sandy",
+	"2018-12-05": "National Day of Mourning for George H.W. Bush",
+	"2025-01-09": "National Day of Mourning for Jimmy Carter",
+}
+
+// cmeClosedHolidays are the NYSE holidays on which CME equity futures do not trade at all.
+// On the other NYSE holidays they trade a shortened session that halts at 13:00 New York time.
+var cmeClosedHolidays = []string{"New Year's Day", "Good Friday", "Christmas Day"}
+
+// NYSEHoliday returns the name of the NYSE holiday on date, if the exchange is closed for one.
+// Weekends are not holidays.
+func NYSEHoliday(date time.Time) (string, bool) {
+	date = startOfDay(date)
+	if name, ok := nyseSpecialClosures[date.Format("2006-01-02")]; ok {
+		return name, true
+	}
+	year := date.Year()
+	type holiday struct {
+		name string
+		day  time.Time
+	}
+	holidays := []holiday{
+		{"Martin Luther King Jr. Day", nthWeekday(year, time.January, time.Monday, 3)},
+		{"Washington's Birthday", nthWeekday(year, time.February, time.Monday, 3)},
+		{"Good Friday", easter(year).AddDate(0, 0, -2)},
+		{"Memorial Day", lastWeekday(year, time.May, time.Monday)},
+		{"Independence Day", observed(time.Date(year, time.July, 4, 0, 0, 0, 0, MarketLocation))},
+		{"Labor Day", nthWeekday(year, time.September, time.Monday, 1)},
+		{"Thanksgiving Day", nthWeekday(year, time.November, time.Thursday, 4)},
+		{"Christmas Day", observed(time.Date(year, time.December, 25, 0, 0, 0, 0, MarketLocation))},
+	}
+	// New Year's Day moves to Monday when it falls on a Sunday, but is not observed
+	// on the Friday before when it falls on a Saturday.
+	newYear := time.Date(year, time.January, 1, 0, 0, 0, 0, MarketLocation)
+	if newYear.Weekday() == time.Sunday {
+		newYear = newYear.AddDate(0, 0, 1)
+	}
+	if newYear.Weekday() != time.Saturday {
+		holidays = append(holidays, holiday{"New Year's Day", newYear})
+	}
+	if year >= 2022 {
+		holidays = append(holidays, holiday{"Juneteenth", observed(time.Date(year, time.June, 19, 0, 0, 0, 0, MarketLocation))})
+	}
+
+	for _, h := range holidays {
+		if h.day.Equal(date) {
+			return h.name, true
+		}
+	}
+	return "", false
+}
+
+// NYSEEarlyClose reports whether the NYSE closes at 13:00 on date: the day before Independence Day,
+// the day after Thanksgiving and Christmas Eve, when they are trading days.
+func NYSEEarlyClose(date time.Time) bool {
+	date = startOfDay(date)
+	if !nyseTradingDay(date) {
+		return false
+	}
+	year := date.Year()
+	switch {
+	case date.Equal(nthWeekday(year, time.November, time.Thursday, 4).AddDate(0, 0, 1)):
+		return true
+	case date.Month() == time.July && date.Day() == 3, date.Month() == time.December && date.Day() == 24:
+		return true
+	}
+	return false
+}
+
+func nyseTradingDay(date time.Time) bool {
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := NYSEHoliday(date)
+	return !holiday
+}
+
+// OfflineHours produces market hours for EQUITY, OPTION and FUTURE from built in NYSE and CME
+// holiday and early close rules, without calling the API.
+//
+// EQUITY follows the NYSE with a 07:00 pre market and a post market until 20:00, or 17:00 after
+// an early close. OPTION has regular sessions only. FUTURE approximates the CME Globex equity index
+// products, trading from 18:00 the previous evening until 17:00.
+//
+// When Live is set every day is also fetched from it. The live hours are returned when available,
+// and days on which the rules disagree with them are reported to OnMismatch.
+type OfflineHours struct {
+	Live       HoursSource
+	OnMismatch func(HoursMismatch)
+}
+
+// HoursMismatch is a day on which the offline rules disagree with the live hours.
+type HoursMismatch struct {
+	Market  Market
+	Date    time.Time
+	Offline *TradingDay
+	Live    *TradingDay
+}
+
+// Hours implements HoursSource.
+func (o *OfflineHours) Hours(ctx context.Context, market Market, date time.Time) (*MarketHours, error) {
+	offline, err := OfflineMarketHours(market, date)
+	if err != nil {
+		return nil, err
+	}
+	if o.Live == nil {
+		return offline, nil
+	}
+	live, err := o.Live.Hours(ctx, market, date)
+	if err != nil {
+		return offline, nil
+	}
+	mismatch, err := CompareHours(market, date, offline, live)
+	if err != nil {
+		return nil, err
+	}
+	if mismatch != nil && o.OnMismatch != nil {
+		o.OnMismatch(*mismatch)
+	}
+	return live, nil
+}
+
+// CompareHours compares the regular sessions of offline and live hours for a day.
+// It returns nil when they agree.
+func CompareHours(market Market, date time.Time, offline, live *MarketHours) (*HoursMismatch, error) {
+	market = Market(strings.ToUpper(string(market)))
+	product := offlineProducts[market]
+	offDay, err := newTradingDay(startOfDay(date), offline, product)
+	if err != nil {
+		return nil, err
+	}
+	liveDay, err := newTradingDay(startOfDay(date), live, product)
+	if err != nil {
+		return nil, err
+	}
+	same := offDay.IsOpen == liveDay.IsOpen && len(offDay.RegularMarket) == len(liveDay.RegularMarket)
+	for i := 0; same && i < len(offDay.RegularMarket); i++ {
+		a, b := offDay.RegularMarket[i], liveDay.RegularMarket[i]
+		same = a.Start.Equal(b.Start) && a.End.Equal(b.End)
+	}
+	if same {
+		return nil, nil
+	}
+	return &HoursMismatch{Market: market, Date: offDay.Date, Offline: offDay, Live: liveDay}, nil
+}
+
+// offlineProducts is the product OfflineMarketHours produces for each market.
+var offlineProducts = map[Market]string{
+	MarketEquity: "EQ",
+	MarketOption: "EQO",
+	MarketFuture: "/ES",
+}
+
+// OfflineMarketHours returns the market hours the offline rules give a market on date,
+// in the same shape as MarketHoursService.GetMarketHours.
+func OfflineMarketHours(market Market, date time.Time) (*MarketHours, error) {
+	market = Market(strings.ToUpper(string(market)))
+	product, ok := offlineProducts[market]
+	if !ok {
+		return nil, fmt.Errorf("no offline rules for market %s", market)
+	}
+	date = startOfDay(date)
+	if date.Before(offlineRulesStart) {
+		return nil, fmt.Errorf("offline rules start at %s", offlineRulesStart.Format("2006-01-02"))
+	}
+
+	at := func(d time.Time, hour, min int) string {
+		return time.Date(d.Year(), d.Month(), d.Day(), hour, min, 0, 0, MarketLocation).Format(time.RFC3339)
+	}
+	key := strings.ToLower(string(market))
+	h := &Hours{
+		Date:        date.Format("2006-01-02"),
+		MarketType:  string(market),
+		Product:     product,
+		ProductName: key,
+	}
+
+	switch market {
+	case MarketEquity, MarketOption:
+		if !nyseTradingDay(date) {
+			// The API reports closed days under the market's own name.
+			h.Product = key
+			return &MarketHours{key: {key: h}}, nil
+		}
+		h.IsOpen = true
+		closeHour, postCloseHour := 16, 20
+		if NYSEEarlyClose(date) {
+			closeHour, postCloseHour = 13, 17
+		}
+		h.SessionHours.RegularMarket = []Period{{Start: at(date, 9, 30), End: at(date, closeHour, 0)}}
+		if market == MarketEquity {
+			h.SessionHours.PreMarket = []Period{{Start: at(date, 7, 0), End: at(date, 9, 30)}}
+			h.SessionHours.PostMarket = []Period{{Start: at(date, closeHour, 0), End: at(date, postCloseHour, 0)}}
+		}
+	case MarketFuture:
+		holiday, isHoliday := NYSEHoliday(date)
+		weekend := date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+		if weekend || isHoliday && contains(holiday, cmeClosedHolidays) {
+			h.Product = key
+			return &MarketHours{key: {key: h}}, nil
+		}
+		h.IsOpen = true
+		end := at(date, 17, 0)
+		switch {
+		case isHoliday:
+			end = at(date, 13, 0)
+		case NYSEEarlyClose(date):
+			end = at(date, 13, 15)
+		}
+		h.SessionHours.RegularMarket = []Period{{Start: at(date.AddDate(0, 0, -1), 18, 0), End: end}}
+	}
+	return &MarketHours{key: {product: h}}, nil
+}
+
+// observed moves a holiday falling on a Saturday to the Friday before and one falling on a Sunday to the Monday after.
+func observed(day time.Time) time.Time {
+	switch day.Weekday() {
+	case time.Saturday:
+		return day.AddDate(0, 0, -1)
+	case time.Sunday:
+		return day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// nthWeekday returns the nth weekday of a month, counting from 1.
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, MarketLocation)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// lastWeekday returns the last weekday of a month.
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, MarketLocation)
+	offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -offset)
+}
+
+// easter returns Easter Sunday using the anonymous Gregorian algorithm.
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, MarketLocation)
+}
@@ -0,0 +1,82 @@
+package tdameritrade
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNYSEHoliday(t *testing.T) {
+	holidays := map[string]string{
+		"2021-01-01": "New Year's Day",
+		"2021-01-18": "Martin Luther King Jr. Day",
+		"2021-04-02": "Good Friday",
+		"2021-05-31": "Memorial Day",
+		"2021-07-05": "Independence Day",
+		"2021-12-24": "Christmas Day",
+		"2022-06-20": "Juneteenth",
+		"2023-01-02": "New Year's Day",
+		"2024-03-29": "Good Friday",
+		"2024-11-28": "Thanksgiving Day",
+		"2025-01-09": "National Day of Mourning for Jimmy Carter",
+	}
+	for date, want := range holidays {
+		d, _ := time.ParseInLocation("2006-01-02", date, MarketLocation)
+		if name, ok := NYSEHoliday(d); !ok || name != want {
+			t.Errorf("%s: expected %q, got %q %v", date, want, name, ok)
+		}
+	}
+	for _, date := range []string{"2021-12-31", "2021-06-18", "2024-07-03", "2024-11-29"} {
+		d, _ := time.ParseInLocation("2006-01-02", date, MarketLocation)
+		if name, ok := NYSEHoliday(d); ok {
+			t.Errorf("%s: unexpected holiday %q", date, name)
+		}
+	}
+}
+
+func TestNYSEEarlyClose(t *testing.T) {
+	for date, want := range map[string]bool{
+		"2023-07-03": true,
+		"2024-07-03": true,
+		"2024-11-29": true,
+		"2024-12-24": true,
+		"2020-07-03": false, // Independence Day observed
+		"2021-12-24": false, // Christmas Day observed
+		"2024-12-23": false,
+	} {
+		d, _ := time.ParseInLocation("2006-01-02", date, MarketLocation)
+		if got := NYSEEarlyClose(d); got != want {
+			t.Errorf("%s: expected early close %v, got %v", date, want, got)
+		}
+	}
+}
+
+func TestOfflineHoursCrossCheck(t *testing.T) {
+	ctx := context.Background()
+	var mismatches []HoursMismatch
+	offline := &OfflineHours{
+		// The live source knows nothing of the holiday on 2021-01-18.
+		Live:       &weekdayHours{},
+		OnMismatch: func(m HoursMismatch) { mismatches = append(mismatches, m) },
+	}
+	cal := NewMarketCalendarWithSource(offline, "EQUITY")
+	days, err := cal.TradingDaysBetween(ctx, time.Date(2021, 1, 15, 0, 0, 0, 0, MarketLocation), time.Date(2021, 1, 19, 0, 0, 0, 0, MarketLocation))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 3 {
+		t.Errorf("expected the live hours to be used, got %v", days)
+	}
+	if len(mismatches) != 1 || mismatches[0].Date.Day() != 18 || mismatches[0].Offline.IsOpen {
+		t.Errorf("expected a single mismatch on the holiday, got %+v", mismatches)
+	}
+
+	hours, err := OfflineMarketHours("FUTURE", time.Date(2021, 1, 18, 0, 0, 0, 0, MarketLocation))
+	if err != nil {
+		t.Fatal(err)
+	}
+	es := (*hours)["future"]["/ES"]
+	if es == nil || es.SessionHours.RegularMarket[0].End != "2021-01-18T13:00:00-05:00" {
+		t.Errorf("expected futures to halt early on the holiday, got %+v", es)
+	}
+}
@@ -0,0 +1,29 @@
+package tdameritrade
+
+import (
+	"time"
+	// TD Ameritrade's market data is timestamped in New York time, so make sure
+	// the zone is available even on systems without a time zone database.
+	_ "time/tzdata"
+)
+
+// MarketLocation is the America/New_York time zone that US markets trade in.
+var MarketLocation = mustLoadLocation("America/New_York")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// EpochMillis converts a time to the epoch milliseconds used by TD Ameritrade's timestamps.
+func EpochMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// FromEpochMillis converts a TD Ameritrade epoch millisecond timestamp to a time in MarketLocation.
+func FromEpochMillis(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond)).In(MarketLocation)
+}
@@ -0,0 +1,134 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MoverSnapshot is the movers of several indexes at one time.
+type MoverSnapshot struct {
+	Time time.Time
+	Sets map[MoverIndex]*MoverSet
+}
+
+// MoverSampler records movers snapshots at a fixed interval over a trading session.
+type MoverSampler struct {
+	Interval time.Duration
+	// Indexes to sample. With none every index in MoverIndexes is sampled.
+	Indexes []MoverIndex
+	// Calendar, if set, limits sampling to the regular session. Run then returns
+	// once the session it sampled has closed.
+	Calendar *MarketCalendar
+	// OnSnapshot, if set, is called with every snapshot as it is taken.
+	OnSnapshot func(MoverSnapshot)
+
+	client *Client
+}
+
+// NewMoverSampler returns a sampler taking a snapshot every interval during the equity market's regular session.
+func NewMoverSampler(client *Client, interval time.Duration, indexes ...MoverIndex) *MoverSampler {
+	return &MoverSampler{
+		Interval: interval,
+		Indexes:  indexes,
+		Calendar: NewMarketCalendar(client, MarketEquity),
+		client:   client,
+	}
+}
+
+// Run samples until ctx is done or, with a Calendar, the session closes, and returns the snapshots taken.
+// The snapshots are also returned alongside any error that stopped sampling.
+func (s *MoverSampler) Run(ctx context.Context) ([]MoverSnapshot, error) {
+	if s.Interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive")
+	}
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	var snapshots []MoverSnapshot
+	for {
+		now := time.Now()
+		open := true
+		if s.Calendar != nil {
+			var err error
+			if open, err = s.Calendar.IsOpen(ctx, now); err != nil {
+				return snapshots, err
+			}
+			if !open && len(snapshots) > 0 {
+				return snapshots, nil
+			}
+		}
+		if open {
+			sets, err := s.client.Mover.AllMovers(ctx, s.Indexes...)
+			if err != nil {
+				return snapshots, err
+			}
+			snapshot := MoverSnapshot{Time: now, Sets: sets}
+			snapshots = append(snapshots, snapshot)
+			if s.OnSnapshot != nil {
+				s.OnSnapshot(snapshot)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return snapshots, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// MoverLeadership summarizes how a symbol featured among the movers of a series of snapshots.
+type MoverLeadership struct {
+	Symbol string
+	// Appearances is the number of snapshots in which the symbol was among the movers of any list.
+	Appearances int
+	FirstSeen   time.Time
+	LastSeen    time.Time
+	// BestRank is the symbol's best position in any list, counting from 1.
+	BestRank int
+}
+
+// Leadership ranks the symbols appearing in snapshots by how often they appeared, then by best rank.
+func Leadership(snapshots []MoverSnapshot) []MoverLeadership {
+	bySymbol := map[string]*MoverLeadership{}
+	for _, snapshot := range snapshots {
+		seen := map[string]bool{}
+		for _, set := range snapshot.Sets {
+			for _, list := range [][]Mover{set.UpValue, set.DownValue, set.UpPercent, set.DownPercent} {
+				for rank, m := range list {
+					l, ok := bySymbol[m.Symbol]
+					if !ok {
+						l = &MoverLeadership{Symbol: m.Symbol, FirstSeen: snapshot.Time, BestRank: rank + 1}
+						bySymbol[m.Symbol] = l
+					}
+					if rank+1 < l.BestRank {
+						l.BestRank = rank + 1
+					}
+					if !seen[m.Symbol] {
+						seen[m.Symbol] = true
+						l.Appearances++
+						l.LastSeen = snapshot.Time
+					}
+				}
+			}
+		}
+	}
+
+	leaders := make([]MoverLeadership, 0, len(bySymbol))
+	for _, l := range bySymbol {
+		leaders = append(leaders, *l)
+	}
+	sort.Slice(leaders, func(i, j int) bool {
+		a, b := leaders[i], leaders[j]
+		if a.Appearances != b.Appearances {
+			return a.Appearances > b.Appearances
+		}
+		if a.BestRank != b.BestRank {
+			return a.BestRank < b.BestRank
+		}
+		return a.Symbol < b.Symbol
+	})
+	return leaders
+}
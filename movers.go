@@ -3,6 +3,8 @@ package tdameritrade
 import (
 	"context"
 	"fmt"
+	"time"
+
 	"github.com/google/go-querystring/query"
 )
 
@@ -16,6 +18,18 @@ var (
 	DirectionTypes = []string{"up", "down"}
 )
 
+// MoverIndex is an index whose movers can be requested.
+type MoverIndex string
+
+const (
+	IndexCompx MoverIndex = "$COMPX"
+	IndexDJI   MoverIndex = "$DJI"
+	IndexSPX   MoverIndex = "$SPX.X"
+)
+
+// MoverIndexes lists every index the movers API supports.
+var MoverIndexes = []MoverIndex{IndexCompx, IndexDJI, IndexSPX}
+
 type MoverService struct {
 	client *Client
 }
@@ -34,8 +48,11 @@ type Mover struct {
 	Symbol      string  `json:"symbol"`
 }
 
-func (s *MoverService) Mover(ctx context.Context, symbol string, opts *MoverOptions) (*[]Mover, *Response, error) {
-	u := fmt.Sprintf("marketdata/%s/movers", symbol)
+func (s *MoverService) Mover(ctx context.Context, index MoverIndex, opts *MoverOptions) (*[]Mover, *Response, error) {
+	if !index.valid() {
+		return nil, nil, fmt.Errorf("invalid index, must have the value of one of the following %v", MoverIndexes)
+	}
+	u := fmt.Sprintf("marketdata/%s/movers", index)
 	if opts != nil {
 		if err := opts.validate(); err != nil {
 			return nil, nil, err
@@ -67,6 +84,67 @@ func (s *MoverService) Mover(ctx context.Context, symbol string, opts *MoverOpti
 	return movers, resp, nil
 }
 
+// MoverSet holds the up and down movers of an index by both value and percent change.
+type MoverSet struct {
+	Index       MoverIndex
+	Time        time.Time
+	UpValue     []Mover
+	DownValue   []Mover
+	UpPercent   []Mover
+	DownPercent []Mover
+}
+
+// AllMovers fetches the up and down movers by value and by percent for each index, a few requests
+// at a time. The first failed request cancels the rest.
+// With no indexes it fetches every index in MoverIndexes.
+func (s *MoverService) AllMovers(ctx context.Context, indexes ...MoverIndex) (map[MoverIndex]*MoverSet, error) {
+	if len(indexes) == 0 {
+		indexes = MoverIndexes
+	}
+
+	type moverJob struct {
+		index MoverIndex
+		opts  MoverOptions
+		dst   *[]Mover
+	}
+	var jobs []moverJob
+	sets := map[MoverIndex]*MoverSet{}
+	now := time.Now()
+	for _, index := range indexes {
+		set := &MoverSet{Index: index, Time: now}
+		sets[index] = set
+		jobs = append(jobs,
+			moverJob{index, MoverOptions{Direction: "up", ChangeType: "value"}, &set.UpValue},
+			moverJob{index, MoverOptions{Direction: "down", ChangeType: "value"}, &set.DownValue},
+			moverJob{index, MoverOptions{Direction: "up", ChangeType: "percent"}, &set.UpPercent},
+			moverJob{index, MoverOptions{Direction: "down", ChangeType: "percent"}, &set.DownPercent},
+		)
+	}
+
+	err := fanOut(ctx, len(jobs), requestWorkers, 0, func(ctx context.Context, i int) error {
+		job := jobs[i]
+		movers, _, err := s.Mover(ctx, job.index, &job.opts)
+		if err != nil {
+			return err
+		}
+		*job.dst = *movers
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+func (index MoverIndex) valid() bool {
+	for _, i := range MoverIndexes {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
+
 func (opts *MoverOptions) validate() error {
 	if opts.ChangeType != "" {
 		if !contains(opts.ChangeType, ChangeTypes) {
@@ -81,7 +159,7 @@ func (opts *MoverOptions) validate() error {
 			return fmt.Errorf("invalid direction, must have the value of one of the following %v", DirectionTypes)
 		}
 	} else {
-		opts.ChangeType = defaultDirectionType
+		opts.Direction = defaultDirectionType
 	}
 
 	return nil
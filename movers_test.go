@@ -0,0 +1,56 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestMoverOptionsDefaults(t *testing.T) {
+	opts := &MoverOptions{ChangeType: "value"}
+	if err := opts.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Direction != defaultDirectionType || opts.ChangeType != "value" {
+		t.Errorf("expected the default direction and the given change type, got %+v", opts)
+	}
+}
+
+func TestAllMovers(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		json.NewEncoder(w).Encode([]Mover{{Symbol: q.Get("direction") + "-" + q.Get("change")}})
+	})
+
+	sets, err := c.Mover.AllMovers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != len(MoverIndexes) {
+		t.Fatalf("expected movers for every index, got %d", len(sets))
+	}
+	set := sets[IndexDJI]
+	if set.UpValue[0].Symbol != "up-value" || set.DownValue[0].Symbol != "down-value" ||
+		set.UpPercent[0].Symbol != "up-percent" || set.DownPercent[0].Symbol != "down-percent" {
+		t.Errorf("movers were not fetched into the right lists: %+v", set)
+	}
+
+	leaders := Leadership([]MoverSnapshot{{Sets: sets}, {Sets: map[MoverIndex]*MoverSet{IndexSPX: {UpValue: []Mover{{Symbol: "B"}, {Symbol: "up-value"}}}}}})
+	if leaders[0].Symbol != "up-value" || leaders[0].Appearances != 2 || leaders[0].BestRank != 1 {
+		t.Errorf("unexpected leader %+v", leaders[0])
+	}
+
+	if _, _, err := c.Mover.Mover(context.Background(), "SPY", nil); err == nil {
+		t.Error("expected an error for an unsupported index")
+	}
+}
+
+func TestAllMoversError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	if _, err := c.Mover.AllMovers(context.Background(), IndexDJI, IndexSPX); err == nil {
+		t.Error("expected a failed request to fail AllMovers")
+	}
+}
@@ -0,0 +1,156 @@
+package tdameritrade
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tdOptionDateLayout  = "010206"
+	occOptionDateLayout = "060102"
+	occSymbolLength     = 21
+	occRootLength       = 6
+	// maxOCCStrike is the largest strike that fits the OCC format's 5 integer and 3 decimal digits.
+	maxOCCStrike = 99999.999
+)
+
+// OptionSymbol is a parsed option contract symbol.
+// It converts between TD Ameritrade's format, e.g. AAPL_011521C130,
+// and the 21 character OCC format, e.g. "AAPL  210115C00130000".
+type OptionSymbol struct {
+	Underlying string
+	// Expiration is the expiration date at midnight UTC.
+	Expiration time.Time
+	// PutCall is "CALL" or "PUT", matching OptionA.PutCall and ExpDateOption.PutCall.
+	PutCall string
+	Strike  float64
+}
+
+// ParseOptionSymbol parses a TD Ameritrade option symbol such as AAPL_011521C130 or SPY_011521P327.5.
+func ParseOptionSymbol(symbol string) (OptionSymbol, error) {
+	i := strings.LastIndex(symbol, "_")
+	if i <= 0 || len(symbol) < i+9 {
+		return OptionSymbol{}, fmt.Errorf("invalid option symbol %q", symbol)
+	}
+	rest := symbol[i+1:]
+
+	expiration, err := time.Parse(tdOptionDateLayout, rest[:6])
+	if err != nil {
+		return OptionSymbol{}, fmt.Errorf("invalid option symbol %q: %v", symbol, err)
+	}
+	putCall, err := parsePutCall(rest[6])
+	if err != nil {
+		return OptionSymbol{}, fmt.Errorf("invalid option symbol %q: %v", symbol, err)
+	}
+	strike, err := strconv.ParseFloat(rest[7:], 64)
+	if err != nil {
+		return OptionSymbol{}, fmt.Errorf("invalid option symbol %q: %v", symbol, err)
+	}
+	if !(strike > 0) || math.IsInf(strike, 1) {
+		return OptionSymbol{}, fmt.Errorf("invalid option symbol %q: strike must be positive", symbol)
+	}
+
+	return OptionSymbol{
+		Underlying: symbol[:i],
+		Expiration: expiration,
+		PutCall:    putCall,
+		Strike:     strike,
+	}, nil
+}
+
+// ParseOCCSymbol parses a 21 character OCC option symbol such as "AAPL  210115C00130000".
+// Trailing spaces in the root may be omitted.
+func ParseOCCSymbol(symbol string) (OptionSymbol, error) {
+	if len(symbol) < 16 || len(symbol) > occSymbolLength {
+		return OptionSymbol{}, fmt.Errorf("invalid OCC symbol %q", symbol)
+	}
+	rest := symbol[len(symbol)-15:]
+
+	expiration, err := time.Parse(occOptionDateLayout, rest[:6])
+	if err != nil {
+		return OptionSymbol{}, fmt.Errorf("invalid OCC symbol %q: %v", symbol, err)
+	}
+	putCall, err := parsePutCall(rest[6])
+	if err != nil {
+		return OptionSymbol{}, fmt.Errorf("invalid OCC symbol %q: %v", symbol, err)
+	}
+	digits := rest[7:]
+	if strings.Trim(digits, "0123456789") != "" {
+		return OptionSymbol{}, fmt.Errorf("invalid OCC symbol %q: strike must be 8 digits", symbol)
+	}
+	strike, err := strconv.Atoi(digits)
+	if err != nil {
+		return OptionSymbol{}, fmt.Errorf("invalid OCC symbol %q: %v", symbol, err)
+	}
+	if strike == 0 {
+		return OptionSymbol{}, fmt.Errorf("invalid OCC symbol %q: strike must be positive", symbol)
+	}
+	underlying := strings.TrimSpace(symbol[:len(symbol)-15])
+	if underlying == "" {
+		return OptionSymbol{}, fmt.Errorf("invalid OCC symbol %q: missing root", symbol)
+	}
+
+	return OptionSymbol{
+		Underlying: underlying,
+		Expiration: expiration,
+		PutCall:    putCall,
+		Strike:     float64(strike) / 1000,
+	}, nil
+}
+
+// String formats the symbol in TD Ameritrade's format.
+func (o OptionSymbol) String() string {
+	return fmt.Sprintf("%s_%s%c%s", o.Underlying, o.Expiration.Format(tdOptionDateLayout), o.putCallLetter(), strconv.FormatFloat(o.Strike, 'f', -1, 64))
+}
+
+// OCC formats the symbol as a 21 character OCC symbol. It fails for underlyings longer than
+// 6 characters and for strikes that are not positive or above 99999.999.
+func (o OptionSymbol) OCC() (string, error) {
+	if o.Underlying == "" || len(o.Underlying) > occRootLength {
+		return "", fmt.Errorf("invalid OCC root %q, must be 1 to %d characters", o.Underlying, occRootLength)
+	}
+	strike := math.Round(o.Strike * 1000)
+	if !(strike > 0) || strike > maxOCCStrike*1000 {
+		return "", fmt.Errorf("invalid OCC strike %v, must be above 0 and at most %v", o.Strike, maxOCCStrike)
+	}
+	return fmt.Sprintf("%-*s%s%c%08d", occRootLength, o.Underlying, o.Expiration.Format(occOptionDateLayout), o.putCallLetter(), int64(strike)), nil
+}
+
+func (o OptionSymbol) putCallLetter() byte {
+	if o.PutCall == "PUT" {
+		return 'P'
+	}
+	return 'C'
+}
+
+func parsePutCall(c byte) (string, error) {
+	switch c {
+	case 'C':
+		return "CALL", nil
+	case 'P':
+		return "PUT", nil
+	default:
+		return "", fmt.Errorf("invalid put/call indicator %q", c)
+	}
+}
+
+// OptionSymbol parses the instrument's symbol.
+func (o *OptionA) OptionSymbol() (OptionSymbol, error) {
+	return ParseOptionSymbol(o.Symbol)
+}
+
+// OptionSymbol parses the option's symbol.
+func (o *ExpDateOption) OptionSymbol() (OptionSymbol, error) {
+	return ParseOptionSymbol(o.Symbol)
+}
+
+// OptionSymbol parses the instrument's symbol. It fails for instruments that are not options.
+func (t *TransactionInstrument) OptionSymbol() (OptionSymbol, error) {
+	if t.AssetType != "OPTION" {
+		return OptionSymbol{}, fmt.Errorf("%s is not an option", t.Symbol)
+	}
+	return ParseOptionSymbol(t.Symbol)
+}
@@ -0,0 +1,86 @@
+package tdameritrade
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseOptionSymbol(t *testing.T) {
+	tests := []struct {
+		td     string
+		occ    string
+		strike float64
+	}{
+		{"AAPL_011521C130", "AAPL  210115C00130000", 130},
+		{"SPY_123121P327.5", "SPY   211231P00327500", 327.5},
+		{"BRK.B_061821C250", "BRK.B 210618C00250000", 250},
+	}
+
+	for _, test := range tests {
+		o, err := ParseOptionSymbol(test.td)
+		if err != nil {
+			t.Fatalf("%s: %v", test.td, err)
+		}
+		if o.Strike != test.strike {
+			t.Errorf("%s: expected strike %v, got %v", test.td, test.strike, o.Strike)
+		}
+		if got, err := o.OCC(); err != nil || got != test.occ {
+			t.Errorf("%s: expected OCC %q, got %q, %v", test.td, test.occ, got, err)
+		}
+		if got := o.String(); got != test.td {
+			t.Errorf("%s: expected round trip, got %s", test.td, got)
+		}
+
+		occ, err := ParseOCCSymbol(test.occ)
+		if err != nil {
+			t.Fatalf("%s: %v", test.occ, err)
+		}
+		if occ != o {
+			t.Errorf("%s: expected %+v, got %+v", test.occ, o, occ)
+		}
+	}
+}
+
+func TestParseOptionSymbolFields(t *testing.T) {
+	o, err := (&OptionA{Symbol: "AAPL_011521P130"}).OptionSymbol()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Underlying != "AAPL" || o.PutCall != "PUT" || !o.Expiration.Equal(time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected option symbol %+v", o)
+	}
+}
+
+func TestParseOptionSymbolInvalid(t *testing.T) {
+	for _, s := range []string{"AAPL", "AAPL_011521X130", "AAPL_131521C130", "_011521C130"} {
+		if _, err := ParseOptionSymbol(s); err == nil {
+			t.Errorf("%s: expected error", s)
+		}
+	}
+	for _, s := range []string{"AAPL_011521C-130", "AAPL_011521C0", "AAPL_011521CNaN", "AAPL_011521CInf"} {
+		if _, err := ParseOptionSymbol(s); err == nil {
+			t.Errorf("%s: expected invalid strike to be rejected", s)
+		}
+	}
+	for _, s := range []string{"AAPL  210115C0013000X", "AAPL  210115C-0130000", "AAPL  210115C+0130000", "AAPL  210115C00000000"} {
+		if _, err := ParseOCCSymbol(s); err == nil {
+			t.Errorf("%q: expected invalid OCC strike to be rejected", s)
+		}
+	}
+}
+
+func TestOCCLimits(t *testing.T) {
+	expiration := time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)
+	if got, err := (OptionSymbol{Underlying: "BRK", Expiration: expiration, PutCall: "CALL", Strike: 99999.999}).OCC(); err != nil || got != "BRK   210115C99999999" {
+		t.Errorf("expected the largest strike to be formatted, got %q, %v", got, err)
+	}
+	for _, o := range []OptionSymbol{
+		{Underlying: "TOOLONG", Expiration: expiration, Strike: 10},
+		{Underlying: "BRK", Expiration: expiration, Strike: 100000},
+		{Underlying: "BRK", Expiration: expiration, Strike: 0},
+	} {
+		if got, err := o.OCC(); err == nil {
+			t.Errorf("%+v: expected an error, got %q", o, got)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package tdameritrade
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultTaggedOrderAttempts   = 3
+	defaultTaggedOrderRetryDelay = time.Second
+)
+
+// TaggedOrderOptions controls how PlaceTaggedOrder recovers from ambiguous failures.
+type TaggedOrderOptions struct {
+	// MaxAttempts is the maximum number of times the order is submitted. Defaults to 3.
+	MaxAttempts int
+	// RetryDelay is how long to wait after an ambiguous failure before looking the order up,
+	// giving TD Ameritrade time to register it. Defaults to one second.
+	RetryDelay time.Duration
+}
+
+// NewOrderTag returns a random client order ID suitable for Order.Tag.
+func NewOrderTag() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// PlaceTaggedOrder submits an order stamped with a client order ID so it can be retried safely.
+// If order.Tag is empty a new tag from NewOrderTag is assigned to it.
+//
+// When a submission fails without a definite answer from TD Ameritrade (a network error or a 5xx
+// response), the account's recent orders are searched for the tag. If the order is found it is
+// returned and not submitted again; otherwise the order is resubmitted, up to opts.MaxAttempts times.
+// A returned Order is only set when the order was recovered by the lookup.
+func (s *AccountsService) PlaceTaggedOrder(ctx context.Context, accountID string, order *Order, opts *TaggedOrderOptions) (*Order, *Response, error) {
+	if order == nil {
+		return nil, nil, fmt.Errorf("order is nil")
+	}
+	if order.Tag == "" {
+		tag, err := NewOrderTag()
+		if err != nil {
+			return nil, nil, err
+		}
+		order.Tag = tag
+	}
+
+	attempts, delay := defaultTaggedOrderAttempts, defaultTaggedOrderRetryDelay
+	if opts != nil {
+		if opts.MaxAttempts > 0 {
+			attempts = opts.MaxAttempts
+		}
+		if opts.RetryDelay > 0 {
+			delay = opts.RetryDelay
+		}
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		var resp *Response
+		resp, err = s.PlaceOrder(ctx, accountID, order)
+		if err == nil || !isAmbiguous(err) {
+			return nil, resp, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		existing, resp, lookupErr := s.findTaggedOrder(ctx, accountID, order.Tag)
+		if lookupErr != nil {
+			return nil, resp, fmt.Errorf("order %s may have been placed, lookup failed: %v (submit error: %v)", order.Tag, lookupErr, err)
+		}
+		if existing != nil {
+			return existing, resp, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("order %s was not placed after %d attempts: %v", order.Tag, attempts, err)
+}
+
+// findTaggedOrder looks through the orders entered in the account since yesterday for one with the given tag.
+func (s *AccountsService) findTaggedOrder(ctx context.Context, accountID, tag string) (*Order, *Response, error) {
+	now := time.Now()
+	orders, resp, err := s.GetOrderByQuery(ctx, accountID, &OrderParams{
+		From: now.AddDate(0, 0, -1),
+		To:   now,
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+	for i := range *orders {
+		if (*orders)[i].Tag == tag {
+			return &(*orders)[i], resp, nil
+		}
+	}
+	return nil, resp, nil
+}
+
+// isAmbiguous reports whether err leaves it unknown if TD Ameritrade accepted a request:
+// a 5xx response or a transport error. Errors building the request never reached TD Ameritrade.
+func isAmbiguous(err error) bool {
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp.Response.StatusCode >= http.StatusInternalServerError
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !errors.Is(err, context.Canceled)
+}
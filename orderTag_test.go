@@ -0,0 +1,87 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// taggedOrderServer fails the first posts with the given status codes and records the orders it accepts.
+type taggedOrderServer struct {
+	failures []int
+	// placeOnFailure stores the order even when the post fails, as if only the response was lost.
+	placeOnFailure bool
+	posts          int
+	orders         []Order
+	query          string
+}
+
+func (s *taggedOrderServer) handle(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "POST":
+		var order Order
+		json.NewDecoder(r.Body).Decode(&order)
+		s.posts++
+		if len(s.failures) > 0 {
+			code := s.failures[0]
+			s.failures = s.failures[1:]
+			if s.placeOnFailure {
+				s.orders = append(s.orders, order)
+			}
+			w.WriteHeader(code)
+			return
+		}
+		s.orders = append(s.orders, order)
+	case "GET":
+		s.query = r.URL.RawQuery
+		json.NewEncoder(w).Encode(s.orders)
+	}
+}
+
+func TestPlaceTaggedOrder(t *testing.T) {
+	opts := &TaggedOrderOptions{RetryDelay: time.Millisecond}
+	newOrder := func() *Order {
+		return &Order{OrderType: "MARKET", Tag: "abc"}
+	}
+
+	t.Run("found after 5xx", func(t *testing.T) {
+		server := &taggedOrderServer{failures: []int{http.StatusBadGateway}, placeOnFailure: true}
+		c := newTestClient(t, server.handle)
+		existing, _, err := c.Account.PlaceTaggedOrder(context.Background(), "123", newOrder(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if existing == nil || existing.Tag != "abc" || server.posts != 1 {
+			t.Errorf("expected the order to be recovered without resubmitting, got %+v after %d posts", existing, server.posts)
+		}
+		if q := server.query; !strings.Contains(q, "fromEnteredTime=") || !strings.Contains(q, "toEnteredTime=") {
+			t.Errorf("expected the lookup to send both entered time bounds, got %q", q)
+		}
+	})
+
+	t.Run("resubmitted after 5xx", func(t *testing.T) {
+		server := &taggedOrderServer{failures: []int{http.StatusServiceUnavailable}}
+		c := newTestClient(t, server.handle)
+		existing, _, err := c.Account.PlaceTaggedOrder(context.Background(), "123", newOrder(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if existing != nil || server.posts != 2 || len(server.orders) != 1 {
+			t.Errorf("expected a single resubmission, got %d posts and %d orders", server.posts, len(server.orders))
+		}
+	})
+
+	t.Run("no retry after 4xx", func(t *testing.T) {
+		server := &taggedOrderServer{failures: []int{http.StatusBadRequest}}
+		c := newTestClient(t, server.handle)
+		if _, _, err := c.Account.PlaceTaggedOrder(context.Background(), "123", newOrder(), opts); err == nil {
+			t.Fatal("expected the 4xx error to be returned")
+		}
+		if server.posts != 1 || server.query != "" {
+			t.Errorf("expected no lookup or retry, got %d posts and lookup %q", server.posts, server.query)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package tdameritrade
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/go-querystring/query"
@@ -26,14 +27,16 @@ type PriceHistoryService struct {
 	client *Client
 }
 
-// PriceHistoryOptions is parsed and translated to query options in the https request
+// PriceHistoryOptions is parsed and translated to query options in the https request.
+// StartDate and EndDate are sent as epoch milliseconds. Period counts back from EndDate, or from now,
+// and must be left zero when StartDate is set.
 type PriceHistoryOptions struct {
 	PeriodType            string    `url:"periodType"`
-	Period                int       `url:"period"`
+	Period                int       `url:"period,omitempty"`
 	FrequencyType         string    `url:"frequencyType"`
-	Frequency             int       `url:"frequency"`
-	EndDate               time.Time `url:"endDate,omitempty"`
-	StartDate             time.Time `url:"startDate,omitempty"`
+	Frequency             int       `url:"frequency,omitempty"`
+	EndDate               time.Time `url:"-"`
+	StartDate             time.Time `url:"-"`
 	NeedExtendedHoursData *bool     `url:"needExtendedHoursData"`
 }
 
@@ -44,7 +47,8 @@ type PriceHistory struct {
 }
 
 type Candle struct {
-	Close    float64 `json:"close"`
+	Close float64 `json:"close"`
+	// Datetime is the start of the candle in epoch milliseconds.
 	Datetime int     `json:"datetime"`
 	High     float64 `json:"high"`
 	Low      float64 `json:"low"`
@@ -52,6 +56,11 @@ type Candle struct {
 	Volume   float64 `json:"volume"`
 }
 
+// Time returns the start of the candle in the America/New_York time zone.
+func (c Candle) Time() time.Time {
+	return FromEpochMillis(int64(c.Datetime))
+}
+
 // PriceHistory get the price history for a symbol
 // TDAmeritrade API Docs: https://developer.tdameritrade.com/price-history/apis/get/marketdata/%7Bsymbol%7D/pricehistory
 func (s *PriceHistoryService) PriceHistory(ctx context.Context, symbol string, opts *PriceHistoryOptions) (*PriceHistory, *Response, error) {
@@ -64,6 +73,12 @@ func (s *PriceHistoryService) PriceHistory(ctx context.Context, symbol string, o
 		if err != nil {
 			return nil, nil, err
 		}
+		if !opts.StartDate.IsZero() {
+			q.Set("startDate", strconv.FormatInt(EpochMillis(opts.StartDate), 10))
+		}
+		if !opts.EndDate.IsZero() {
+			q.Set("endDate", strconv.FormatInt(EpochMillis(opts.EndDate), 10))
+		}
 		u = fmt.Sprintf("%s?%s", u, q.Encode())
 	}
 
@@ -97,7 +112,15 @@ func (opts *PriceHistoryOptions) validate() error {
 			return fmt.Errorf("invalid frequencyType, must have the value of one of the following %v", validFrequencyTypes)
 		}
 	} else {
-		opts.PeriodType = defaultFrequencyType
+		opts.FrequencyType = defaultFrequencyType
+	}
+
+	if opts.Period != 0 && !opts.StartDate.IsZero() {
+		return fmt.Errorf("invalid period, must be zero when startDate is set")
+	}
+
+	if !opts.StartDate.IsZero() && !opts.EndDate.IsZero() && opts.EndDate.Before(opts.StartDate) {
+		return fmt.Errorf("invalid date range, endDate is before startDate")
 	}
 
 	return nil
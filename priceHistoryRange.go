@@ -0,0 +1,103 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxMinuteHistoryWindow is the longest range of minute candles TD Ameritrade returns in one call.
+const maxMinuteHistoryWindow = 10 * 24 * time.Hour
+
+var validMinuteFrequencies = []int{1, 5, 10, 15, 30}
+
+// PriceHistoryWindow is a time range fetched by a single PriceHistory call.
+type PriceHistoryWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// PriceHistoryRange fetches minute candles for a symbol between from and to, splitting the range
+// into windows TD Ameritrade allows in a single call and fetching them concurrently.
+// frequency is the candle size in minutes and must be 1, 5, 10, 15 or 30.
+// The candles are de-duplicated and ordered by time. Windows that came back empty are returned
+// separately rather than as an error; any other failure aborts the whole range.
+func (s *PriceHistoryService) PriceHistoryRange(ctx context.Context, symbol string, from, to time.Time, frequency int) ([]Candle, []PriceHistoryWindow, error) {
+	if !containsInt(frequency, validMinuteFrequencies) {
+		return nil, nil, fmt.Errorf("invalid frequency, must have the value of one of the following %v", validMinuteFrequencies)
+	}
+	if !to.After(from) {
+		return nil, nil, fmt.Errorf("invalid date range, to must be after from")
+	}
+
+	windows := splitPriceHistoryRange(from, to, maxMinuteHistoryWindow)
+
+	var (
+		mu      sync.Mutex
+		candles = map[int]Candle{}
+		empty   []PriceHistoryWindow
+	)
+	err := fanOut(ctx, len(windows), requestWorkers, requestInterval, func(ctx context.Context, i int) error {
+		w := windows[i]
+		ph, _, err := s.PriceHistory(ctx, symbol, &PriceHistoryOptions{
+			PeriodType:    "day",
+			FrequencyType: "minute",
+			Frequency:     frequency,
+			StartDate:     w.Start,
+			EndDate:       w.End,
+		})
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case ph != nil && ph.Empty:
+			empty = append(empty, w)
+		case err != nil:
+			return err
+		default:
+			for _, c := range ph.Candles {
+				candles[c.Datetime] = c
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]Candle, 0, len(candles))
+	for _, c := range candles {
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Datetime < result[j].Datetime
+	})
+	sort.Slice(empty, func(i, j int) bool {
+		return empty[i].Start.Before(empty[j].Start)
+	})
+	return result, empty, nil
+}
+
+// splitPriceHistoryRange splits [from, to] into consecutive windows no longer than size.
+func splitPriceHistoryRange(from, to time.Time, size time.Duration) []PriceHistoryWindow {
+	var windows []PriceHistoryWindow
+	for start := from; start.Before(to); start = start.Add(size) {
+		end := start.Add(size)
+		if end.After(to) {
+			end = to
+		}
+		windows = append(windows, PriceHistoryWindow{Start: start, End: end})
+	}
+	return windows
+}
+
+func containsInt(i int, lst []int) bool {
+	for _, e := range lst {
+		if e == i {
+			return true
+		}
+	}
+	return false
+}
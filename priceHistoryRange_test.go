@@ -0,0 +1,100 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSplitPriceHistoryRange(t *testing.T) {
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, MarketLocation)
+	day := 24 * time.Hour
+
+	windows := splitPriceHistoryRange(from, from.Add(25*day), 10*day)
+	want := []PriceHistoryWindow{
+		{Start: from, End: from.Add(10 * day)},
+		{Start: from.Add(10 * day), End: from.Add(20 * day)},
+		{Start: from.Add(20 * day), End: from.Add(25 * day)},
+	}
+	if !reflect.DeepEqual(windows, want) {
+		t.Errorf("expected %v, got %v", want, windows)
+	}
+
+	if windows := splitPriceHistoryRange(from, from.Add(20*day), 10*day); len(windows) != 2 {
+		t.Errorf("expected an exact multiple to split into 2 windows, got %v", windows)
+	}
+	if windows := splitPriceHistoryRange(from, from, 10*day); len(windows) != 0 {
+		t.Errorf("expected no windows for an empty range, got %v", windows)
+	}
+}
+
+func TestPriceHistoryRange(t *testing.T) {
+	interval := requestInterval
+	requestInterval = time.Millisecond
+	defer func() { requestInterval = interval }()
+
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, MarketLocation)
+	to := from.Add(25 * 24 * time.Hour)
+	millis := func(t time.Time) int { return int(EpochMillis(t)) }
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.ParseInt(r.URL.Query().Get("startDate"), 10, 64)
+		end, _ := strconv.ParseInt(r.URL.Query().Get("endDate"), 10, 64)
+		if FromEpochMillis(start).Equal(from.Add(10 * 24 * time.Hour)) {
+			json.NewEncoder(w).Encode(PriceHistory{Symbol: "AAPL", Empty: true})
+			return
+		}
+		// each window returns a candle at both of its ends, so windows overlap on their boundaries
+		json.NewEncoder(w).Encode(PriceHistory{Symbol: "AAPL", Candles: []Candle{
+			{Datetime: int(end), Close: 2},
+			{Datetime: int(start), Close: 1},
+		}})
+	})
+
+	candles, empty, err := c.PriceHistory.PriceHistoryRange(context.Background(), "AAPL", from, to, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var times []int
+	for _, candle := range candles {
+		times = append(times, candle.Datetime)
+	}
+	wantTimes := []int{
+		millis(from),
+		millis(from.Add(10 * 24 * time.Hour)),
+		millis(from.Add(20 * 24 * time.Hour)),
+		millis(to),
+	}
+	if !reflect.DeepEqual(times, wantTimes) {
+		t.Errorf("expected de-duplicated candles at %v, got %v", wantTimes, times)
+	}
+	wantEmpty := []PriceHistoryWindow{{Start: from.Add(10 * 24 * time.Hour), End: from.Add(20 * 24 * time.Hour)}}
+	if !reflect.DeepEqual(empty, wantEmpty) {
+		t.Errorf("expected empty windows %v, got %v", wantEmpty, empty)
+	}
+
+	if _, _, err := c.PriceHistory.PriceHistoryRange(context.Background(), "AAPL", from, to, 3); err == nil {
+		t.Error("expected an invalid frequency to be rejected")
+	}
+	if _, _, err := c.PriceHistory.PriceHistoryRange(context.Background(), "AAPL", to, from, 5); err == nil {
+		t.Error("expected a reversed range to be rejected")
+	}
+}
+
+func TestPriceHistoryRangeError(t *testing.T) {
+	interval := requestInterval
+	requestInterval = time.Millisecond
+	defer func() { requestInterval = interval }()
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, MarketLocation)
+	if _, _, err := c.PriceHistory.PriceHistoryRange(context.Background(), "AAPL", from, from.AddDate(0, 0, 30), 1); err == nil {
+		t.Error("expected a failed window to fail the range")
+	}
+}
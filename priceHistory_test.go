@@ -0,0 +1,72 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestPriceHistoryQuery(t *testing.T) {
+	var query url.Values
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		json.NewEncoder(w).Encode(PriceHistory{Symbol: "AAPL", Candles: []Candle{{Close: 1}}})
+	})
+
+	start := time.Date(2021, 1, 4, 9, 30, 0, 0, MarketLocation)
+	end := time.Date(2021, 1, 8, 16, 0, 0, 0, MarketLocation)
+	_, _, err := c.PriceHistory.PriceHistory(context.Background(), "AAPL", &PriceHistoryOptions{
+		PeriodType: "day",
+		Frequency:  5,
+		StartDate:  start,
+		EndDate:    end,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"periodType":    "day",
+		"frequencyType": defaultFrequencyType,
+		"frequency":     "5",
+		"startDate":     strconv.FormatInt(start.UnixNano()/int64(time.Millisecond), 10),
+		"endDate":       strconv.FormatInt(end.UnixNano()/int64(time.Millisecond), 10),
+	}
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("expected %s=%s, got %q", key, value, got)
+		}
+	}
+	if _, ok := query["period"]; ok {
+		t.Error("expected unset period to be omitted")
+	}
+
+	_, _, err = c.PriceHistory.PriceHistory(context.Background(), "AAPL", &PriceHistoryOptions{
+		StartDate: end,
+		EndDate:   start,
+	})
+	if err == nil {
+		t.Error("expected an end date before the start date to be rejected")
+	}
+
+	_, _, err = c.PriceHistory.PriceHistory(context.Background(), "AAPL", &PriceHistoryOptions{
+		Period:    5,
+		StartDate: start,
+		EndDate:   end,
+	})
+	if err == nil {
+		t.Error("expected a period together with a start date to be rejected")
+	}
+
+	_, _, err = c.PriceHistory.PriceHistory(context.Background(), "AAPL", &PriceHistoryOptions{
+		PeriodType: "day",
+		Period:     5,
+		EndDate:    end,
+	})
+	if err != nil {
+		t.Errorf("expected a period counting back from the end date to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Alert is raised by a QuoteMonitor when a rule fires.
+type Alert struct {
+	Symbol   string
+	Message  string
+	Time     time.Time
+	Previous *Quote
+	Current  *Quote
+}
+
+// AlertRule inspects two successive quotes of a symbol. It returns a message and true to raise an alert.
+// Rules are only called from the monitor's polling goroutine, so they may keep state without locking.
+type AlertRule interface {
+	Check(symbol string, previous, current *Quote) (string, bool)
+}
+
+// AlertRuleFunc adapts a function to the AlertRule interface.
+type AlertRuleFunc func(symbol string, previous, current *Quote) (string, bool)
+
+// Check calls f.
+func (f AlertRuleFunc) Check(symbol string, previous, current *Quote) (string, bool) {
+	return f(symbol, previous, current)
+}
+
+// quotePrices returns the last, bid, ask and previous close prices of a quote. Futures, future
+// options and forex only report them in the *InDouble fields of their Data.
+func quotePrices(q *Quote) (last, bid, ask, close float64) {
+	switch data := q.Data.(type) {
+	case *FutureQuote:
+		return data.LastPriceInDouble, data.BidPriceInDouble, data.AskPriceInDouble, data.ClosePriceInDouble
+	case *FutureOptionQuote:
+		return data.LastPriceInDouble, data.BidPriceInDouble, data.AskPriceInDouble, data.ClosePriceInDouble
+	case *ForexQuote:
+		return data.LastPriceInDouble, data.BidPriceInDouble, data.AskPriceInDouble, data.ClosePriceInDouble
+	}
+	return q.LastPrice, q.BidPrice, q.AskPrice, q.ClosePrice
+}
+
+// PriceCross fires when the last price crosses Level in either direction.
+type PriceCross struct {
+	Level float64
+}
+
+// Check implements AlertRule.
+func (r PriceCross) Check(symbol string, previous, current *Quote) (string, bool) {
+	before, _, _, _ := quotePrices(previous)
+	after, _, _, _ := quotePrices(current)
+	if before < r.Level && after >= r.Level {
+		return fmt.Sprintf("%s crossed above %v at %v", symbol, r.Level, after), true
+	}
+	if before > r.Level && after <= r.Level {
+		return fmt.Sprintf("%s crossed below %v at %v", symbol, r.Level, after), true
+	}
+	return "", false
+}
+
+// PercentMove fires when the move from the previous close first exceeds Percent in either direction.
+type PercentMove struct {
+	Percent float64
+}
+
+// Check implements AlertRule.
+func (r PercentMove) Check(symbol string, previous, current *Quote) (string, bool) {
+	before, after := percentChange(previous), percentChange(current)
+	if math.Abs(before) < r.Percent && math.Abs(after) >= r.Percent {
+		return fmt.Sprintf("%s moved %.2f%% from the previous close", symbol, after), true
+	}
+	return "", false
+}
+
+func percentChange(q *Quote) float64 {
+	last, _, _, close := quotePrices(q)
+	if close == 0 {
+		return 0
+	}
+	return (last - close) / close * 100
+}
+
+// VolumeSpike fires when the volume traded between two polls is more than Multiple times
+// the average volume per poll seen so far for the symbol.
+type VolumeSpike struct {
+	Multiple float64
+
+	total map[string]float64
+	polls map[string]int
+}
+
+// Check implements AlertRule.
+func (r *VolumeSpike) Check(symbol string, previous, current *Quote) (string, bool) {
+	if r.total == nil {
+		r.total, r.polls = map[string]float64{}, map[string]int{}
+	}
+	traded := current.TotalVolume - previous.TotalVolume
+	if traded < 0 {
+		// volume resets at the start of a new day
+		r.total[symbol], r.polls[symbol] = 0, 0
+		return "", false
+	}
+	polls := r.polls[symbol]
+	average := 0.0
+	if polls > 0 {
+		average = r.total[symbol] / float64(polls)
+	}
+	r.total[symbol] += traded
+	r.polls[symbol]++
+	if polls > 0 && average > 0 && traded > r.Multiple*average {
+		return fmt.Sprintf("%s traded %v shares, %.1fx its average", symbol, traded, traded/average), true
+	}
+	return "", false
+}
+
+// SpreadWidening fires when the bid/ask spread first exceeds MaxSpread as a fraction of the midpoint.
+type SpreadWidening struct {
+	MaxSpread float64
+}
+
+// Check implements AlertRule.
+func (r SpreadWidening) Check(symbol string, previous, current *Quote) (string, bool) {
+	before, after := relativeSpread(previous), relativeSpread(current)
+	if before <= r.MaxSpread && after > r.MaxSpread {
+		return fmt.Sprintf("%s spread widened to %.2f%% of the midpoint", symbol, after*100), true
+	}
+	return "", false
+}
+
+func relativeSpread(q *Quote) float64 {
+	_, bid, ask, _ := quotePrices(q)
+	mid := (bid + ask) / 2
+	if bid <= 0 || ask <= 0 || mid == 0 {
+		return 0
+	}
+	return (ask - bid) / mid
+}
+
+// QuoteMonitor polls quotes for a set of symbols and raises alerts when rules fire
+// on the change between successive snapshots.
+type QuoteMonitor struct {
+	Symbols  []string
+	Interval time.Duration
+	Rules    []AlertRule
+
+	// Market is the market whose hours the monitor follows. It only polls while that market
+	// is in a session. An empty Market polls all the time.
+	Market Market
+	// ExtendedHours also polls during the pre and post market sessions.
+	ExtendedHours bool
+
+	// OnAlert, if set, is called for every alert.
+	OnAlert func(Alert)
+	// Alerts, if set, receives every alert. Sends block, so the channel must be drained.
+	Alerts chan<- Alert
+	// OnError, if set, is called with the error of every failed poll.
+	OnError func(error)
+
+	client   *Client
+	previous Quotes
+	hoursDay time.Time
+	hours    *MarketHours
+}
+
+// NewQuoteMonitor returns a monitor polling the given symbols every interval.
+func NewQuoteMonitor(client *Client, interval time.Duration, symbols ...string) *QuoteMonitor {
+	return &QuoteMonitor{
+		Symbols:  symbols,
+		Interval: interval,
+		Market:   MarketEquity,
+		client:   client,
+	}
+}
+
+// Run polls until ctx is done. A failed poll is reported to OnError and polling carries on
+// at the next interval.
+func (m *QuoteMonitor) Run(ctx context.Context) error {
+	if m.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+	for {
+		if err := m.Poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if m.OnError != nil {
+				m.OnError(err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll takes one snapshot if the market is open and raises alerts against the previous snapshot.
+func (m *QuoteMonitor) Poll(ctx context.Context) error {
+	open, err := m.marketOpen(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if !open {
+		return nil
+	}
+
+	quotes, _, err := m.client.Quotes.GetQuotes(ctx, m.Symbols...)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for symbol, current := range *quotes {
+		previous, ok := m.previous[symbol]
+		if !ok {
+			continue
+		}
+		for _, rule := range m.Rules {
+			if message, fire := rule.Check(symbol, previous, current); fire {
+				if err := m.raise(ctx, Alert{Symbol: symbol, Message: message, Time: now, Previous: previous, Current: current}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	m.previous = *quotes
+	return nil
+}
+
+func (m *QuoteMonitor) raise(ctx context.Context, alert Alert) error {
+	if m.OnAlert != nil {
+		m.OnAlert(alert)
+	}
+	if m.Alerts != nil {
+		select {
+		case m.Alerts <- alert:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// marketOpen checks t against the day's market hours, fetching them once per day.
+func (m *QuoteMonitor) marketOpen(ctx context.Context, t time.Time) (bool, error) {
+	if m.Market == "" {
+		return true, nil
+	}
+	day := startOfDay(t)
+	if m.hours == nil || !day.Equal(m.hoursDay) {
+		hours, _, err := m.client.MarketHours.GetMarketHours(ctx, m.Market, day)
+		if err != nil {
+			return false, err
+		}
+		m.hours, m.hoursDay = hours, day
+	}
+
+	for _, products := range *m.hours {
+		for _, h := range products {
+			if !h.IsOpen {
+				continue
+			}
+			sessions := h.SessionHours.RegularMarket
+			if m.ExtendedHours {
+				sessions = append(append(append([]Period{}, h.SessionHours.PreMarket...), sessions...), h.SessionHours.PostMarket...)
+			}
+			for _, p := range sessions {
+				in, err := p.Contains(t)
+				if err != nil {
+					return false, err
+				}
+				if in {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,117 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPriceCross(t *testing.T) {
+	rule := PriceCross{Level: 100}
+	if _, fire := rule.Check("XYZ", &Quote{LastPrice: 99}, &Quote{LastPrice: 100}); !fire {
+		t.Error("expected a cross above the level to fire")
+	}
+	if _, fire := rule.Check("XYZ", &Quote{LastPrice: 101}, &Quote{LastPrice: 99.5}); !fire {
+		t.Error("expected a cross below the level to fire")
+	}
+	if _, fire := rule.Check("XYZ", &Quote{LastPrice: 100}, &Quote{LastPrice: 101}); fire {
+		t.Error("expected staying above the level not to fire")
+	}
+
+	// futures only report prices in Data
+	future := func(last float64) *Quote {
+		return &Quote{AssetType: "FUTURE", Data: &FutureQuote{LastPriceInDouble: last}}
+	}
+	if _, fire := (PriceCross{Level: 3800}).Check("/ES", future(3795.5), future(3801)); !fire {
+		t.Error("expected a future crossing the level to fire")
+	}
+}
+
+func TestPercentMove(t *testing.T) {
+	rule := PercentMove{Percent: 5}
+	if _, fire := rule.Check("XYZ", &Quote{LastPrice: 104, ClosePrice: 100}, &Quote{LastPrice: 95, ClosePrice: 100}); !fire {
+		t.Error("expected a 5% fall from the close to fire")
+	}
+	if _, fire := rule.Check("XYZ", &Quote{LastPrice: 106, ClosePrice: 100}, &Quote{LastPrice: 107, ClosePrice: 100}); fire {
+		t.Error("expected a move already beyond the threshold not to fire again")
+	}
+	if _, fire := rule.Check("XYZ", &Quote{LastPrice: 104}, &Quote{LastPrice: 200}); fire {
+		t.Error("expected a quote without a close not to fire")
+	}
+
+	future := func(last float64) *Quote {
+		return &Quote{AssetType: "FUTURE", Data: &FutureQuote{LastPriceInDouble: last, ClosePriceInDouble: 4000}}
+	}
+	if _, fire := rule.Check("/ES", future(4100), future(4200)); !fire {
+		t.Error("expected a future moving 5% from its close to fire")
+	}
+}
+
+func TestVolumeSpike(t *testing.T) {
+	rule := &VolumeSpike{Multiple: 3}
+	volumes := []float64{0, 100, 200, 300, 1000, 1100, 50}
+	var fired []int
+	for i := 1; i < len(volumes); i++ {
+		if _, fire := rule.Check("XYZ", &Quote{TotalVolume: volumes[i-1]}, &Quote{TotalVolume: volumes[i]}); fire {
+			fired = append(fired, i)
+		}
+	}
+	// 700 shares against an average of 100, and nothing after the daily reset
+	if len(fired) != 1 || fired[0] != 4 {
+		t.Errorf("expected only the jump to 1000 to fire, got polls %v", fired)
+	}
+	if _, fire := rule.Check("ABC", &Quote{TotalVolume: 0}, &Quote{TotalVolume: 5000}); fire {
+		t.Error("expected the first poll of another symbol not to fire")
+	}
+}
+
+func TestSpreadWidening(t *testing.T) {
+	rule := SpreadWidening{MaxSpread: 0.01}
+	if _, fire := rule.Check("XYZ", &Quote{BidPrice: 99.9, AskPrice: 100.1}, &Quote{BidPrice: 99, AskPrice: 101}); !fire {
+		t.Error("expected a spread of 2% to fire")
+	}
+	if _, fire := rule.Check("XYZ", &Quote{BidPrice: 99, AskPrice: 101}, &Quote{BidPrice: 98, AskPrice: 102}); fire {
+		t.Error("expected an already wide spread not to fire again")
+	}
+	if _, fire := rule.Check("XYZ", &Quote{BidPrice: 99.9, AskPrice: 100.1}, &Quote{AskPrice: 101}); fire {
+		t.Error("expected a one sided quote not to fire")
+	}
+
+	future := func(bid, ask float64) *Quote {
+		return &Quote{AssetType: "FUTURE", Data: &FutureQuote{BidPriceInDouble: bid, AskPriceInDouble: ask}}
+	}
+	if _, fire := rule.Check("/ES", future(3800, 3800.25), future(3780, 3820)); !fire {
+		t.Error("expected a future's spread widening to fire")
+	}
+}
+
+func TestQuoteMonitorKeepsPollingAfterErrors(t *testing.T) {
+	var requests int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		if n == 2 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(Quotes{"XYZ": {Symbol: "XYZ", LastPrice: float64(98 + n)}})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var errs []error
+	m := NewQuoteMonitor(c, time.Millisecond, "XYZ")
+	m.Market = ""
+	m.Rules = []AlertRule{PriceCross{Level: 100.5}}
+	m.OnError = func(err error) { errs = append(errs, err) }
+	m.OnAlert = func(Alert) { cancel() }
+
+	if err := m.Run(ctx); err != context.Canceled {
+		t.Fatalf("expected the monitor to run until cancelled, got %v", err)
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected the failed poll to be reported once, got %v", errs)
+	}
+}
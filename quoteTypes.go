@@ -0,0 +1,333 @@
+package tdameritrade
+
+import "encoding/json"
+
+type _Quote Quote
+
+// EquityQuote is the quote of an EQUITY or ETF.
+type EquityQuote struct {
+	AssetType                          string  `json:"assetType"`
+	AssetMainType                      string  `json:"assetMainType"`
+	Cusip                              string  `json:"cusip"`
+	AssetSubType                       string  `json:"assetSubType"`
+	Symbol                             string  `json:"symbol"`
+	Description                        string  `json:"description"`
+	BidPrice                           float64 `json:"bidPrice"`
+	BidSize                            float64 `json:"bidSize"`
+	BidID                              string  `json:"bidId"`
+	AskPrice                           float64 `json:"askPrice"`
+	AskSize                            float64 `json:"askSize"`
+	AskID                              string  `json:"askId"`
+	LastPrice                          float64 `json:"lastPrice"`
+	LastSize                           float64 `json:"lastSize"`
+	LastID                             string  `json:"lastId"`
+	OpenPrice                          float64 `json:"openPrice"`
+	HighPrice                          float64 `json:"highPrice"`
+	LowPrice                           float64 `json:"lowPrice"`
+	BidTick                            string  `json:"bidTick"`
+	ClosePrice                         float64 `json:"closePrice"`
+	NetChange                          float64 `json:"netChange"`
+	TotalVolume                        float64 `json:"totalVolume"`
+	QuoteTimeInLong                    int64   `json:"quoteTimeInLong"`
+	TradeTimeInLong                    int64   `json:"tradeTimeInLong"`
+	Mark                               float64 `json:"mark"`
+	Exchange                           string  `json:"exchange"`
+	ExchangeName                       string  `json:"exchangeName"`
+	Marginable                         bool    `json:"marginable"`
+	Shortable                          bool    `json:"shortable"`
+	Volatility                         float64 `json:"volatility"`
+	Digits                             int     `json:"digits"`
+	Five2WkHigh                        float64 `json:"52WkHigh"`
+	Five2WkLow                         float64 `json:"52WkLow"`
+	NAV                                float64 `json:"nAV"`
+	PeRatio                            float64 `json:"peRatio"`
+	DivAmount                          float64 `json:"divAmount"`
+	DivYield                           float64 `json:"divYield"`
+	DivDate                            string  `json:"divDate"`
+	SecurityStatus                     string  `json:"securityStatus"`
+	RegularMarketLastPrice             float64 `json:"regularMarketLastPrice"`
+	RegularMarketLastSize              int     `json:"regularMarketLastSize"`
+	RegularMarketNetChange             float64 `json:"regularMarketNetChange"`
+	RegularMarketTradeTimeInLong       int64   `json:"regularMarketTradeTimeInLong"`
+	NetPercentChangeInDouble           float64 `json:"netPercentChangeInDouble"`
+	MarkChangeInDouble                 float64 `json:"markChangeInDouble"`
+	MarkPercentChangeInDouble          float64 `json:"markPercentChangeInDouble"`
+	RegularMarketPercentChangeInDouble float64 `json:"regularMarketPercentChangeInDouble"`
+	Delayed                            bool    `json:"delayed"`
+}
+
+// OptionQuote is the quote of an OPTION, including its Greeks.
+type OptionQuote struct {
+	AssetType                 string  `json:"assetType"`
+	AssetMainType             string  `json:"assetMainType"`
+	Cusip                     string  `json:"cusip"`
+	Symbol                    string  `json:"symbol"`
+	Description               string  `json:"description"`
+	BidPrice                  float64 `json:"bidPrice"`
+	BidSize                   float64 `json:"bidSize"`
+	AskPrice                  float64 `json:"askPrice"`
+	AskSize                   float64 `json:"askSize"`
+	LastPrice                 float64 `json:"lastPrice"`
+	LastSize                  float64 `json:"lastSize"`
+	OpenPrice                 float64 `json:"openPrice"`
+	HighPrice                 float64 `json:"highPrice"`
+	LowPrice                  float64 `json:"lowPrice"`
+	ClosePrice                float64 `json:"closePrice"`
+	NetChange                 float64 `json:"netChange"`
+	TotalVolume               float64 `json:"totalVolume"`
+	QuoteTimeInLong           int64   `json:"quoteTimeInLong"`
+	TradeTimeInLong           int64   `json:"tradeTimeInLong"`
+	Mark                      float64 `json:"mark"`
+	OpenInterest              float64 `json:"openInterest"`
+	Volatility                float64 `json:"volatility"`
+	MoneyIntrinsicValue       float64 `json:"moneyIntrinsicValue"`
+	Multiplier                float64 `json:"multiplier"`
+	Digits                    int     `json:"digits"`
+	StrikePrice               float64 `json:"strikePrice"`
+	ContractType              string  `json:"contractType"`
+	Underlying                string  `json:"underlying"`
+	ExpirationDay             int     `json:"expirationDay"`
+	ExpirationMonth           int     `json:"expirationMonth"`
+	ExpirationYear            int     `json:"expirationYear"`
+	DaysToExpiration          int     `json:"daysToExpiration"`
+	TimeValue                 float64 `json:"timeValue"`
+	Deliverables              string  `json:"deliverables"`
+	Delta                     float64 `json:"delta"`
+	Gamma                     float64 `json:"gamma"`
+	Theta                     float64 `json:"theta"`
+	Vega                      float64 `json:"vega"`
+	Rho                       float64 `json:"rho"`
+	SecurityStatus            string  `json:"securityStatus"`
+	TheoreticalOptionValue    float64 `json:"theoreticalOptionValue"`
+	UnderlyingPrice           float64 `json:"underlyingPrice"`
+	UvExpirationType          string  `json:"uvExpirationType"`
+	Exchange                  string  `json:"exchange"`
+	ExchangeName              string  `json:"exchangeName"`
+	LastTradingDay            int64   `json:"lastTradingDay"`
+	SettlementType            string  `json:"settlementType"`
+	NetPercentChangeInDouble  float64 `json:"netPercentChangeInDouble"`
+	MarkChangeInDouble        float64 `json:"markChangeInDouble"`
+	MarkPercentChangeInDouble float64 `json:"markPercentChangeInDouble"`
+	ImpliedYield              float64 `json:"impliedYield"`
+	IsPennyPilot              bool    `json:"isPennyPilot"`
+	Delayed                   bool    `json:"delayed"`
+}
+
+// IndexQuote is the quote of an INDEX such as $SPX.X.
+type IndexQuote struct {
+	AssetType                string  `json:"assetType"`
+	AssetMainType            string  `json:"assetMainType"`
+	Symbol                   string  `json:"symbol"`
+	Description              string  `json:"description"`
+	LastPrice                float64 `json:"lastPrice"`
+	OpenPrice                float64 `json:"openPrice"`
+	HighPrice                float64 `json:"highPrice"`
+	LowPrice                 float64 `json:"lowPrice"`
+	ClosePrice               float64 `json:"closePrice"`
+	NetChange                float64 `json:"netChange"`
+	TotalVolume              float64 `json:"totalVolume"`
+	TradeTimeInLong          int64   `json:"tradeTimeInLong"`
+	Exchange                 string  `json:"exchange"`
+	ExchangeName             string  `json:"exchangeName"`
+	Digits                   int     `json:"digits"`
+	Five2WkHigh              float64 `json:"52WkHigh"`
+	Five2WkLow               float64 `json:"52WkLow"`
+	SecurityStatus           string  `json:"securityStatus"`
+	NetPercentChangeInDouble float64 `json:"netPercentChangeInDouble"`
+	Delayed                  bool    `json:"delayed"`
+}
+
+// MutualFundQuote is the quote of a MUTUAL_FUND.
+type MutualFundQuote struct {
+	AssetType                string  `json:"assetType"`
+	AssetMainType            string  `json:"assetMainType"`
+	Cusip                    string  `json:"cusip"`
+	Symbol                   string  `json:"symbol"`
+	Description              string  `json:"description"`
+	ClosePrice               float64 `json:"closePrice"`
+	NetChange                float64 `json:"netChange"`
+	TotalVolume              float64 `json:"totalVolume"`
+	TradeTimeInLong          int64   `json:"tradeTimeInLong"`
+	Exchange                 string  `json:"exchange"`
+	ExchangeName             string  `json:"exchangeName"`
+	Digits                   int     `json:"digits"`
+	Five2WkHigh              float64 `json:"52WkHigh"`
+	Five2WkLow               float64 `json:"52WkLow"`
+	NAV                      float64 `json:"nAV"`
+	PeRatio                  float64 `json:"peRatio"`
+	DivAmount                float64 `json:"divAmount"`
+	DivYield                 float64 `json:"divYield"`
+	DivDate                  string  `json:"divDate"`
+	SecurityStatus           string  `json:"securityStatus"`
+	NetPercentChangeInDouble float64 `json:"netPercentChangeInDouble"`
+	Delayed                  bool    `json:"delayed"`
+}
+
+// FutureQuote is the quote of a FUTURE such as /ES.
+type FutureQuote struct {
+	AssetType                string  `json:"assetType"`
+	AssetMainType            string  `json:"assetMainType"`
+	Symbol                   string  `json:"symbol"`
+	Description              string  `json:"description"`
+	BidPriceInDouble         float64 `json:"bidPriceInDouble"`
+	AskPriceInDouble         float64 `json:"askPriceInDouble"`
+	LastPriceInDouble        float64 `json:"lastPriceInDouble"`
+	BidSizeInLong            int64   `json:"bidSizeInLong"`
+	AskSizeInLong            int64   `json:"askSizeInLong"`
+	LastSizeInLong           int64   `json:"lastSizeInLong"`
+	BidID                    string  `json:"bidId"`
+	AskID                    string  `json:"askId"`
+	LastID                   string  `json:"lastId"`
+	HighPriceInDouble        float64 `json:"highPriceInDouble"`
+	LowPriceInDouble         float64 `json:"lowPriceInDouble"`
+	ClosePriceInDouble       float64 `json:"closePriceInDouble"`
+	OpenPriceInDouble        float64 `json:"openPriceInDouble"`
+	ChangeInDouble           float64 `json:"changeInDouble"`
+	FuturePercentChange      float64 `json:"futurePercentChange"`
+	TotalVolume              float64 `json:"totalVolume"`
+	QuoteTimeInLong          int64   `json:"quoteTimeInLong"`
+	TradeTimeInLong          int64   `json:"tradeTimeInLong"`
+	Mark                     float64 `json:"mark"`
+	Exchange                 string  `json:"exchange"`
+	ExchangeName             string  `json:"exchangeName"`
+	OpenInterest             float64 `json:"openInterest"`
+	Tick                     float64 `json:"tick"`
+	TickAmount               float64 `json:"tickAmount"`
+	Product                  string  `json:"product"`
+	FuturePriceFormat        string  `json:"futurePriceFormat"`
+	FutureTradingHours       string  `json:"futureTradingHours"`
+	FutureIsTradable         bool    `json:"futureIsTradable"`
+	FutureMultiplier         float64 `json:"futureMultiplier"`
+	FutureIsActive           bool    `json:"futureIsActive"`
+	FutureSettlementPrice    float64 `json:"futureSettlementPrice"`
+	FutureActiveSymbol       string  `json:"futureActiveSymbol"`
+	FutureExpirationDate     int64   `json:"futureExpirationDate"`
+	SecurityStatus           string  `json:"securityStatus"`
+	NetPercentChangeInDouble float64 `json:"netPercentChangeInDouble"`
+	Delayed                  bool    `json:"delayed"`
+}
+
+// FutureOptionQuote is the quote of a FUTURE_OPTION.
+type FutureOptionQuote struct {
+	AssetType                   string  `json:"assetType"`
+	AssetMainType               string  `json:"assetMainType"`
+	Symbol                      string  `json:"symbol"`
+	Description                 string  `json:"description"`
+	BidPriceInDouble            float64 `json:"bidPriceInDouble"`
+	AskPriceInDouble            float64 `json:"askPriceInDouble"`
+	LastPriceInDouble           float64 `json:"lastPriceInDouble"`
+	HighPriceInDouble           float64 `json:"highPriceInDouble"`
+	LowPriceInDouble            float64 `json:"lowPriceInDouble"`
+	ClosePriceInDouble          float64 `json:"closePriceInDouble"`
+	OpenPriceInDouble           float64 `json:"openPriceInDouble"`
+	NetChangeInDouble           float64 `json:"netChangeInDouble"`
+	OpenInterest                float64 `json:"openInterest"`
+	Exchange                    string  `json:"exchange"`
+	ExchangeName                string  `json:"exchangeName"`
+	Volatility                  float64 `json:"volatility"`
+	MoneyIntrinsicValueInDouble float64 `json:"moneyIntrinsicValueInDouble"`
+	MultiplierInDouble          float64 `json:"multiplierInDouble"`
+	Digits                      int     `json:"digits"`
+	StrikePriceInDouble         float64 `json:"strikePriceInDouble"`
+	ContractType                string  `json:"contractType"`
+	Underlying                  string  `json:"underlying"`
+	TimeValueInDouble           float64 `json:"timeValueInDouble"`
+	DeltaInDouble               float64 `json:"deltaInDouble"`
+	GammaInDouble               float64 `json:"gammaInDouble"`
+	ThetaInDouble               float64 `json:"thetaInDouble"`
+	VegaInDouble                float64 `json:"vegaInDouble"`
+	RhoInDouble                 float64 `json:"rhoInDouble"`
+	Mark                        float64 `json:"mark"`
+	Tick                        float64 `json:"tick"`
+	TickAmount                  float64 `json:"tickAmount"`
+	FutureIsTradable            bool    `json:"futureIsTradable"`
+	FutureTradingHours          string  `json:"futureTradingHours"`
+	FuturePercentChange         float64 `json:"futurePercentChange"`
+	FutureIsActive              bool    `json:"futureIsActive"`
+	FutureExpirationDate        int64   `json:"futureExpirationDate"`
+	ExpirationType              string  `json:"expirationType"`
+	ExerciseType                string  `json:"exerciseType"`
+	InTheMoney                  bool    `json:"inTheMoney"`
+	Delayed                     bool    `json:"delayed"`
+}
+
+// ForexQuote is the quote of a FOREX pair such as EUR/USD.
+type ForexQuote struct {
+	AssetType                string  `json:"assetType"`
+	AssetMainType            string  `json:"assetMainType"`
+	Symbol                   string  `json:"symbol"`
+	Description              string  `json:"description"`
+	BidPriceInDouble         float64 `json:"bidPriceInDouble"`
+	AskPriceInDouble         float64 `json:"askPriceInDouble"`
+	LastPriceInDouble        float64 `json:"lastPriceInDouble"`
+	BidSizeInLong            int64   `json:"bidSizeInLong"`
+	AskSizeInLong            int64   `json:"askSizeInLong"`
+	LastSizeInLong           int64   `json:"lastSizeInLong"`
+	HighPriceInDouble        float64 `json:"highPriceInDouble"`
+	LowPriceInDouble         float64 `json:"lowPriceInDouble"`
+	ClosePriceInDouble       float64 `json:"closePriceInDouble"`
+	OpenPriceInDouble        float64 `json:"openPriceInDouble"`
+	ChangeInDouble           float64 `json:"changeInDouble"`
+	PercentChange            float64 `json:"percentChange"`
+	TotalVolume              float64 `json:"totalVolume"`
+	QuoteTimeInLong          int64   `json:"quoteTimeInLong"`
+	TradeTimeInLong          int64   `json:"tradeTimeInLong"`
+	Mark                     float64 `json:"mark"`
+	Exchange                 string  `json:"exchange"`
+	ExchangeName             string  `json:"exchangeName"`
+	Digits                   int     `json:"digits"`
+	Tick                     float64 `json:"tick"`
+	TickAmount               float64 `json:"tickAmount"`
+	Product                  string  `json:"product"`
+	TradingHours             string  `json:"tradingHours"`
+	IsTradable               bool    `json:"isTradable"`
+	MarketMaker              string  `json:"marketMaker"`
+	Five2WkHighInDouble      float64 `json:"52WkHighInDouble"`
+	Five2WkLowInDouble       float64 `json:"52WkLowInDouble"`
+	SecurityStatus           string  `json:"securityStatus"`
+	NetPercentChangeInDouble float64 `json:"netPercentChangeInDouble"`
+	Delayed                  bool    `json:"delayed"`
+}
+
+// UnmarshalJSON decodes the fields shared by every quote into Quote and the complete quote
+// into an asset specific type in Data, chosen by AssetType. Quotes of unknown asset types
+// are decoded without Data rather than failing the whole response.
+func (q *Quote) UnmarshalJSON(bs []byte) (err error) {
+	quote := _Quote{}
+
+	err = json.Unmarshal(bs, &quote)
+	if err != nil {
+		return err
+	}
+
+	switch quote.AssetType {
+	case "EQUITY", "ETF":
+		quote.Data = &EquityQuote{}
+	case "OPTION":
+		quote.Data = &OptionQuote{}
+	case "INDEX":
+		quote.Data = &IndexQuote{}
+	case "MUTUAL_FUND":
+		quote.Data = &MutualFundQuote{}
+	case "FUTURE":
+		quote.Data = &FutureQuote{}
+	case "FUTURE_OPTION":
+		quote.Data = &FutureOptionQuote{}
+	case "FOREX":
+		quote.Data = &ForexQuote{}
+	}
+	if quote.Data != nil {
+		err = json.Unmarshal(bs, quote.Data)
+	}
+	*q = Quote(quote)
+
+	return err
+}
+
+// MarshalJSON encodes the asset specific quote in Data when present, so no fields are lost.
+func (q *Quote) MarshalJSON() ([]byte, error) {
+	if q.Data != nil {
+		return json.Marshal(q.Data)
+	}
+	return json.Marshal((*_Quote)(q))
+}
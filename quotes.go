@@ -3,6 +3,9 @@ package tdameritrade
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
+	"sync"
 )
 
 // QuotesService handles communication with the marketdata related methods of
@@ -64,14 +67,56 @@ type Quote struct {
 	MarkPercentChangeInDouble          float64 `json:"markPercentChangeInDouble"`
 	RegularMarketPercentChangeInDouble float64 `json:"regularMarketPercentChangeInDouble"`
 	Delayed                            bool    `json:"delayed"`
+
+	// Data holds the complete quote as one of *EquityQuote, *OptionQuote, *IndexQuote,
+	// *MutualFundQuote, *FutureQuote, *FutureOptionQuote or *ForexQuote, depending on AssetType.
+	Data interface{} `json:"-"`
 }
 
-func (s *QuotesService) GetQuotes(ctx context.Context, symbols string) (*Quotes, *Response, error) {
-	u := "marketdata/quotes"
-	if symbols == "" {
+// GetQuotes gets quotes for one or more symbols. Symbols are escaped, so futures such as /ES and
+// indices such as $SPX.X can be requested directly. For compatibility, an argument may also hold
+// several comma separated symbols. Long symbol lists are split into batches that are fetched
+// concurrently and merged; the returned Response is that of the last batch to complete.
+// TDAmeritrade API Docs: https://developer.tdameritrade.com/quotes/apis/get/marketdata/quotes
+func (s *QuotesService) GetQuotes(ctx context.Context, symbols ...string) (*Quotes, *Response, error) {
+	all := splitSymbols(symbols)
+	if len(all) == 0 {
 		return nil, nil, fmt.Errorf("no symbols present")
 	}
-	u = fmt.Sprintf("%s?symbol=%s", u, symbols)
+
+	batches := symbolBatches(all)
+	if len(batches) == 1 {
+		return s.getQuotes(ctx, batches[0])
+	}
+
+	var (
+		mu       sync.Mutex
+		quotes   = Quotes{}
+		lastResp *Response
+	)
+	err := fanOut(ctx, len(batches), requestWorkers, 0, func(ctx context.Context, i int) error {
+		q, resp, err := s.getQuotes(ctx, batches[i])
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			return err
+		}
+		for symbol, quote := range *q {
+			quotes[symbol] = quote
+		}
+		lastResp = resp
+		return nil
+	})
+	if err != nil {
+		return nil, lastResp, err
+	}
+	return &quotes, lastResp, nil
+}
+
+func (s *QuotesService) getQuotes(ctx context.Context, symbols []string) (*Quotes, *Response, error) {
+	q := url.Values{"symbol": {strings.Join(symbols, ",")}}
+	u := fmt.Sprintf("marketdata/quotes?%s", q.Encode())
 
 	req, err := s.client.NewRequest("GET", u, nil)
 
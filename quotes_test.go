@@ -0,0 +1,82 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetQuotesEscapesAndBatches(t *testing.T) {
+	var requests int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if strings.Contains(r.URL.RawQuery, "/") || strings.Contains(r.URL.RawQuery, "$") {
+			t.Errorf("symbols were not escaped: %s", r.URL.RawQuery)
+		}
+		quotes := Quotes{}
+		for _, symbol := range strings.Split(r.URL.Query().Get("symbol"), ",") {
+			quotes[symbol] = &Quote{Symbol: symbol}
+		}
+		json.NewEncoder(w).Encode(quotes)
+	})
+
+	quotes, _, err := c.Quotes.GetQuotes(context.Background(), "BRK.B", "/ES", "$SPX.X,SPY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, symbol := range []string{"BRK.B", "/ES", "$SPX.X", "SPY"} {
+		if (*quotes)[symbol] == nil {
+			t.Errorf("missing quote for %s", symbol)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+
+	var symbols []string
+	for i := 0; i < 2*maxSymbolsPerRequest+1; i++ {
+		symbols = append(symbols, fmt.Sprintf("S%d", i))
+	}
+	requests = 0
+	quotes, _, err = c.Quotes.GetQuotes(context.Background(), symbols...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*quotes) != len(symbols) {
+		t.Errorf("expected %d merged quotes, got %d", len(symbols), len(*quotes))
+	}
+	if requests < 3 {
+		t.Errorf("expected symbols to be split into at least 3 batches, got %d", requests)
+	}
+}
+
+func TestQuoteUnmarshalAssetTypes(t *testing.T) {
+	quotes := Quotes{}
+	err := json.Unmarshal([]byte(`{
+		"AAPL_011521C130": {"assetType": "OPTION", "symbol": "AAPL_011521C130", "strikePrice": 130, "delta": 0.55, "openInterest": 1200, "mark": 3.1},
+		"/ES": {"assetType": "FUTURE", "symbol": "/ES", "lastPriceInDouble": 3795.5, "futureMultiplier": 50},
+		"XYZ": {"assetType": "SOMETHING_NEW", "symbol": "XYZ", "lastPrice": 1}
+	}`), &quotes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	option, ok := quotes["AAPL_011521C130"].Data.(*OptionQuote)
+	if !ok || option.StrikePrice != 130 || option.Delta != 0.55 || option.OpenInterest != 1200 {
+		t.Errorf("unexpected option quote %+v", quotes["AAPL_011521C130"].Data)
+	}
+	if quotes["AAPL_011521C130"].Mark != 3.1 {
+		t.Errorf("expected common fields to be decoded into Quote")
+	}
+	future, ok := quotes["/ES"].Data.(*FutureQuote)
+	if !ok || future.LastPriceInDouble != 3795.5 || future.FutureMultiplier != 50 {
+		t.Errorf("unexpected future quote %+v", quotes["/ES"].Data)
+	}
+	if quotes["XYZ"].Data != nil || quotes["XYZ"].LastPrice != 1 {
+		t.Errorf("expected unknown asset type to decode without Data, got %+v", quotes["XYZ"])
+	}
+}
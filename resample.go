@@ -0,0 +1,136 @@
+package tdameritrade
+
+import (
+	"sort"
+	"time"
+)
+
+// regularSessionOpen is when the regular US equity session opens, as an offset from midnight New York time.
+const regularSessionOpen = 9*time.Hour + 30*time.Minute
+
+// Resample aggregates candles into bars of the given size. Bars are aligned to the 9:30 New York
+// regular session open of each day, so 65 minute bars split the session into six even bars.
+// The input must be ordered by time. Candle times are in milliseconds, so a size below one
+// millisecond returns the candles unchanged.
+func Resample(candles []Candle, size time.Duration) []Candle {
+	if size < time.Millisecond {
+		return candles
+	}
+	return aggregateCandles(candles, func(t time.Time) time.Time {
+		anchor := startOfDay(t).Add(regularSessionOpen)
+		offset := t.Sub(anchor)
+		buckets := offset / size
+		if offset < 0 && offset%size != 0 {
+			buckets--
+		}
+		return anchor.Add(buckets * size)
+	})
+}
+
+// ResampleDaily aggregates candles into one bar per New York calendar day.
+// Filter the candles with FilterSessions first for bars covering only the regular session.
+func ResampleDaily(candles []Candle) []Candle {
+	return aggregateCandles(candles, startOfDay)
+}
+
+// ResampleWeekly aggregates candles into one bar per week, with weeks starting on Monday.
+func ResampleWeekly(candles []Candle) []Candle {
+	return aggregateCandles(candles, func(t time.Time) time.Time {
+		day := startOfDay(t)
+		back := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -back)
+	})
+}
+
+// FilterSessions keeps the candles that start inside one of the regular market sessions in hours,
+// as returned by MarketHoursService for each day.
+func FilterSessions(candles []Candle, hours []*Hours) ([]Candle, error) {
+	type session struct{ start, end time.Time }
+	var sessions []session
+	for _, h := range hours {
+		for _, p := range h.SessionHours.RegularMarket {
+			start, end, err := p.Parse()
+			if err != nil {
+				return nil, err
+			}
+			sessions = append(sessions, session{start, end})
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].start.Before(sessions[j].start)
+	})
+
+	var filtered []Candle
+	for _, c := range candles {
+		t := c.Time()
+		i := sort.Search(len(sessions), func(i int) bool {
+			return sessions[i].end.After(t)
+		})
+		if i < len(sessions) && !t.Before(sessions[i].start) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered, nil
+}
+
+// FillGaps inserts a flat, zero volume candle at the previous close for every missing bar of the
+// given size between two candles on the same New York day. Gaps across days are left alone.
+// The input must be ordered by time. A size below one millisecond returns the candles unchanged.
+func FillGaps(candles []Candle, size time.Duration) []Candle {
+	if len(candles) == 0 {
+		return nil
+	}
+	if size < time.Millisecond {
+		return candles
+	}
+	step := int(size / time.Millisecond)
+	filled := []Candle{candles[0]}
+	for _, c := range candles[1:] {
+		prev := filled[len(filled)-1]
+		if startOfDay(prev.Time()).Equal(startOfDay(c.Time())) {
+			for t := prev.Datetime + step; t < c.Datetime; t += step {
+				filled = append(filled, Candle{
+					Datetime: t,
+					Open:     prev.Close,
+					High:     prev.Close,
+					Low:      prev.Close,
+					Close:    prev.Close,
+				})
+			}
+		}
+		filled = append(filled, c)
+	}
+	return filled
+}
+
+// aggregateCandles groups consecutive candles by the bucket their time falls in and
+// combines each group into a single OHLCV candle starting at the bucket's time.
+func aggregateCandles(candles []Candle, bucket func(time.Time) time.Time) []Candle {
+	var out []Candle
+	var current time.Time
+	for _, c := range candles {
+		b := bucket(c.Time())
+		if len(out) == 0 || !b.Equal(current) {
+			current = b
+			c.Datetime = int(EpochMillis(b))
+			out = append(out, c)
+			continue
+		}
+		last := &out[len(out)-1]
+		if c.High > last.High {
+			last.High = c.High
+		}
+		if c.Low < last.Low {
+			last.Low = c.Low
+		}
+		last.Close = c.Close
+		last.Volume += c.Volume
+	}
+	return out
+}
+
+// startOfDay returns midnight New York time on the day of t.
+func startOfDay(t time.Time) time.Time {
+	t = t.In(MarketLocation)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, MarketLocation)
+}
@@ -0,0 +1,85 @@
+package tdameritrade
+
+import (
+	"testing"
+	"time"
+)
+
+func minuteCandles(start time.Time, n int) []Candle {
+	candles := make([]Candle, n)
+	for i := range candles {
+		price := float64(100 + i)
+		candles[i] = Candle{
+			Datetime: int(EpochMillis(start.Add(time.Duration(i) * time.Minute))),
+			Open:     price,
+			High:     price + 0.5,
+			Low:      price - 0.5,
+			Close:    price + 0.25,
+			Volume:   10,
+		}
+	}
+	return candles
+}
+
+func TestResample(t *testing.T) {
+	open := time.Date(2021, 1, 15, 9, 30, 0, 0, MarketLocation)
+	bars := Resample(minuteCandles(open.Add(-5*time.Minute), 135), 65*time.Minute)
+	if len(bars) != 3 {
+		t.Fatalf("expected 3 bars, got %d", len(bars))
+	}
+	if !bars[0].Time().Equal(open.Add(-65*time.Minute)) || !bars[1].Time().Equal(open) || !bars[2].Time().Equal(open.Add(65*time.Minute)) {
+		t.Errorf("bars are not aligned to the session open: %v %v %v", bars[0].Time(), bars[1].Time(), bars[2].Time())
+	}
+	b := bars[1]
+	if b.Open != 105 || b.Close != 169.25 || b.High != 169.5 || b.Low != 104.5 || b.Volume != 650 {
+		t.Errorf("unexpected aggregated bar %+v", b)
+	}
+}
+
+func TestResampleWeekly(t *testing.T) {
+	friday := time.Date(2021, 1, 15, 15, 0, 0, 0, MarketLocation)
+	monday := time.Date(2021, 1, 18, 10, 0, 0, 0, MarketLocation)
+	candles := append(minuteCandles(friday, 2), minuteCandles(monday, 2)...)
+	weeks := ResampleWeekly(candles)
+	if len(weeks) != 2 {
+		t.Fatalf("expected 2 weeks, got %d", len(weeks))
+	}
+	if !weeks[0].Time().Equal(time.Date(2021, 1, 11, 0, 0, 0, 0, MarketLocation)) {
+		t.Errorf("expected week to start on monday, got %v", weeks[0].Time())
+	}
+}
+
+func TestFilterSessionsAndFillGaps(t *testing.T) {
+	hours := []*Hours{{
+		SessionHours: SessionHours{
+			RegularMarket: []Period{{Start: "2021-01-15T09:30:00-05:00", End: "2021-01-15T16:00:00-05:00"}},
+		},
+	}}
+	candles := minuteCandles(time.Date(2021, 1, 15, 9, 28, 0, 0, MarketLocation), 5)
+	filtered, err := FilterSessions(candles, hours)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 3 {
+		t.Fatalf("expected 3 regular session candles, got %d", len(filtered))
+	}
+
+	gappy := []Candle{filtered[0], filtered[2]}
+	filled := FillGaps(gappy, time.Minute)
+	if len(filled) != 3 || filled[1].Volume != 0 || filled[1].Close != gappy[0].Close || filled[1].Datetime != filtered[1].Datetime {
+		t.Errorf("unexpected filled candles %+v", filled)
+	}
+}
+
+func TestResampleAndFillGapsRejectTinySizes(t *testing.T) {
+	candles := minuteCandles(time.Date(2021, 1, 15, 9, 30, 0, 0, MarketLocation), 3)
+	candles = append(candles[:1], candles[2])
+	for _, size := range []time.Duration{0, -time.Minute, time.Microsecond} {
+		if bars := Resample(candles, size); len(bars) != len(candles) {
+			t.Errorf("Resample(%v): expected the candles unchanged, got %d bars", size, len(bars))
+		}
+		if filled := FillGaps(candles, size); len(filled) != len(candles) {
+			t.Errorf("FillGaps(%v): expected the candles unchanged, got %d candles", size, len(filled))
+		}
+	}
+}
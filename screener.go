@@ -0,0 +1,79 @@
+package tdameritrade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ScreenFilter reports whether an instrument passes a screen.
+type ScreenFilter func(info *InstrumentInfo) bool
+
+// FundamentalBetween passes instruments whose fundamental field lies within [min, max].
+func FundamentalBetween(field func(f *Fundamental) float64, min, max float64) ScreenFilter {
+	return func(info *InstrumentInfo) bool {
+		if info.Fundamental == nil {
+			return false
+		}
+		v := field(info.Fundamental)
+		return v >= min && v <= max
+	}
+}
+
+// Fundamentals fetches the fundamental data of many symbols, keyed by symbol. Long symbol lists
+// are split into batches like GetQuotes and fetched concurrently.
+func (s *InstrumentService) Fundamentals(ctx context.Context, symbols ...string) (Instruments, error) {
+	all := splitSymbols(symbols)
+	if len(all) == 0 {
+		return nil, fmt.Errorf("no symbols present")
+	}
+
+	batches := symbolBatches(all)
+	var (
+		mu     sync.Mutex
+		result = Instruments{}
+	)
+	err := fanOut(ctx, len(batches), requestWorkers, 0, func(ctx context.Context, i int) error {
+		instruments, _, err := s.SearchInstruments(ctx, strings.Join(batches[i], ","), ProjectionFundamental)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for symbol, info := range *instruments {
+			result[symbol] = info
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Screen fetches the fundamental data of symbols and returns the instruments passing every filter,
+// ordered by symbol.
+func (s *InstrumentService) Screen(ctx context.Context, symbols []string, filters ...ScreenFilter) ([]*InstrumentInfo, error) {
+	instruments, err := s.Fundamentals(ctx, symbols...)
+	if err != nil {
+		return nil, err
+	}
+
+	var passed []*InstrumentInfo
+screen:
+	for _, info := range instruments {
+		for _, filter := range filters {
+			if !filter(info) {
+				continue screen
+			}
+		}
+		passed = append(passed, info)
+	}
+	sort.Slice(passed, func(i, j int) bool {
+		return passed[i].Symbol < passed[j].Symbol
+	})
+	return passed, nil
+}
@@ -0,0 +1,32 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestScreen(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if p := r.URL.Query().Get("projection"); p != "fundamental" {
+			t.Errorf("expected the fundamental projection, got %q", p)
+		}
+		pe := map[string]float64{"AAPL": 30, "F": 8, "XOM": 12}
+		instruments := Instruments{}
+		for _, symbol := range strings.Split(r.URL.Query().Get("symbol"), ",") {
+			instruments[symbol] = &InstrumentInfo{Symbol: symbol, Fundamental: &Fundamental{Symbol: symbol, PeRatio: pe[symbol]}}
+		}
+		json.NewEncoder(w).Encode(instruments)
+	})
+
+	cheap := FundamentalBetween(func(f *Fundamental) float64 { return f.PeRatio }, 5, 15)
+	passed, err := c.Instrument.Screen(context.Background(), []string{"XOM", "AAPL,F"}, cheap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(passed) != 2 || passed[0].Symbol != "F" || passed[1].Symbol != "XOM" {
+		t.Errorf("unexpected screen result %+v", passed)
+	}
+}
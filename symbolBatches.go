@@ -0,0 +1,45 @@
+package tdameritrade
+
+import (
+	"net/url"
+	"strings"
+)
+
+const (
+	// maxSymbolQueryLength keeps request URLs listing many symbols well under common server limits.
+	maxSymbolQueryLength = 1500
+	// maxSymbolsPerRequest is the most symbols listed in a single request.
+	maxSymbolsPerRequest = 300
+)
+
+// splitSymbols returns the symbols in args, each of which may also hold several comma separated symbols.
+func splitSymbols(args []string) []string {
+	var symbols []string
+	for _, arg := range args {
+		for _, symbol := range strings.Split(arg, ",") {
+			if symbol = strings.TrimSpace(symbol); symbol != "" {
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+	return symbols
+}
+
+// symbolBatches splits symbols into batches whose escaped symbol list stays within maxSymbolQueryLength
+// and that hold at most maxSymbolsPerRequest symbols.
+func symbolBatches(symbols []string) [][]string {
+	var batches [][]string
+	var batch []string
+	length := 0
+	for _, symbol := range symbols {
+		// the comma separating symbols is escaped as %2C
+		n := len(url.QueryEscape(symbol)) + 3
+		if len(batch) > 0 && (length+n > maxSymbolQueryLength || len(batch) == maxSymbolsPerRequest) {
+			batches = append(batches, batch)
+			batch, length = nil, 0
+		}
+		batch = append(batch, symbol)
+		length += n
+	}
+	return append(batches, batch)
+}
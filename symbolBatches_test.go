@@ -0,0 +1,20 @@
+package tdameritrade
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSymbolBatches(t *testing.T) {
+	symbols := splitSymbols([]string{" AAPL, MSFT", "", "/ES,,$SPX.X"})
+	if want := []string{"AAPL", "MSFT", "/ES", "$SPX.X"}; !reflect.DeepEqual(symbols, want) {
+		t.Errorf("expected %v, got %v", want, symbols)
+	}
+
+	long := strings.Repeat("X", 400)
+	batches := symbolBatches([]string{long, long, long, long, "A"})
+	if len(batches) != 2 || len(batches[0]) != 3 || len(batches[1]) != 2 {
+		t.Errorf("expected batches of 3 and 2 symbols by query length, got %d batches", len(batches))
+	}
+}
@@ -0,0 +1,453 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SymbolRecord is an instrument known to a SymbolMaster.
+type SymbolRecord struct {
+	Cusip       string    `json:"cusip,omitempty"`
+	Symbol      string    `json:"symbol"`
+	Description string    `json:"description,omitempty"`
+	AssetType   string    `json:"assetType,omitempty"`
+	Exchange    string    `json:"exchange,omitempty"`
+	FirstSeen   time.Time `json:"firstSeen"`
+	LastSeen    time.Time `json:"lastSeen"`
+	// Delisted is set once the instrument can no longer be found.
+	Delisted time.Time `json:"delisted,omitempty"`
+	// PreviousSymbols lists the symbols the instrument traded under before, oldest first.
+	PreviousSymbols []string `json:"previousSymbols,omitempty"`
+}
+
+// IsDelisted reports whether the instrument has been delisted.
+func (r *SymbolRecord) IsDelisted() bool {
+	return !r.Delisted.IsZero()
+}
+
+// SymbolChange records an instrument changing its symbol.
+type SymbolChange struct {
+	Cusip     string    `json:"cusip"`
+	OldSymbol string    `json:"oldSymbol"`
+	NewSymbol string    `json:"newSymbol"`
+	Time      time.Time `json:"time"`
+}
+
+type symbolMasterFile struct {
+	Records []*SymbolRecord `json:"records"`
+	Changes []SymbolChange  `json:"changes"`
+}
+
+// SymbolMaster is a local database of instruments mapping between CUSIP, symbol and description.
+// It is filled from the instruments API or a CSV file and persisted as JSON at Path.
+// Instruments are identified by CUSIP, so a known CUSIP appearing under a new symbol is recorded
+// as a rename, and its old symbols keep resolving to it.
+type SymbolMaster struct {
+	Path string
+
+	service  *InstrumentService
+	mu       sync.RWMutex
+	records  map[string]*SymbolRecord // by CUSIP, or by symbol for instruments without one
+	bySymbol map[string]*SymbolRecord
+	changes  []SymbolChange
+}
+
+// OpenSymbolMaster loads the symbol master stored at path, or starts an empty one if the file does not exist.
+func OpenSymbolMaster(service *InstrumentService, path string) (*SymbolMaster, error) {
+	m := &SymbolMaster{
+		Path:     path,
+		service:  service,
+		records:  map[string]*SymbolRecord{},
+		bySymbol: map[string]*SymbolRecord{},
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file symbolMasterFile
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("corrupt symbol master %s: %v", path, err)
+	}
+	for _, r := range file.Records {
+		m.records[recordKey(r.Cusip, r.Symbol)] = r
+		for _, symbol := range r.PreviousSymbols {
+			m.bySymbol[symbol] = r
+		}
+	}
+	// Current symbols take precedence over symbols that have since been reused.
+	for _, r := range file.Records {
+		m.bySymbol[r.Symbol] = r
+	}
+	m.changes = file.Changes
+	return m, nil
+}
+
+// Save writes the symbol master to Path.
+func (m *SymbolMaster) Save() error {
+	m.mu.RLock()
+	file := symbolMasterFile{Records: m.sortedRecords(), Changes: m.changes}
+	b, err := json.Marshal(file)
+	m.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(m.Path, b)
+}
+
+func recordKey(cusip, symbol string) string {
+	if cusip != "" {
+		return cusip
+	}
+	return "symbol:" + symbol
+}
+
+// Add records an instrument seen at time t, updating an existing record with the same CUSIP,
+// or with the same symbol when either side has no CUSIP.
+func (m *SymbolMaster) Add(info *InstrumentInfo, t time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.add(info, t)
+}
+
+func (m *SymbolMaster) add(info *InstrumentInfo, t time.Time) {
+	if info == nil || info.Symbol == "" {
+		return
+	}
+	key := recordKey(info.Cusip, info.Symbol)
+	r, ok := m.records[key]
+	if !ok && info.Cusip != "" {
+		// an instrument first recorded without a CUSIP takes it on rather than being duplicated
+		if r, ok = m.records[recordKey("", info.Symbol)]; ok {
+			delete(m.records, recordKey("", info.Symbol))
+			r.Cusip = info.Cusip
+			m.records[key] = r
+		}
+	}
+	if !ok && info.Cusip == "" {
+		// and an instrument seen without a CUSIP updates the record of its current symbol
+		if r, ok = m.bySymbol[info.Symbol]; ok && r.Symbol != info.Symbol {
+			ok = false
+		}
+	}
+	if !ok {
+		r = &SymbolRecord{Cusip: info.Cusip, Symbol: info.Symbol, FirstSeen: t}
+		m.records[key] = r
+	}
+	if r.Symbol != info.Symbol {
+		m.changes = append(m.changes, SymbolChange{Cusip: r.Cusip, OldSymbol: r.Symbol, NewSymbol: info.Symbol, Time: t})
+		r.PreviousSymbols = append(r.PreviousSymbols, r.Symbol)
+		r.Symbol = info.Symbol
+	}
+	if info.Description != "" {
+		r.Description = info.Description
+	}
+	if info.Type != "" {
+		r.AssetType = info.Type
+	}
+	if info.Exchange != "" {
+		r.Exchange = info.Exchange
+	}
+	if t.After(r.LastSeen) {
+		r.LastSeen = t
+	}
+	r.Delisted = time.Time{}
+	m.bySymbol[r.Symbol] = r
+}
+
+// MarkDelisted records that the instrument with symbol was delisted at time t.
+func (m *SymbolMaster) MarkDelisted(symbol string, t time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.bySymbol[symbol]
+	if !ok {
+		return fmt.Errorf("unknown symbol %s", symbol)
+	}
+	r.Delisted = t
+	return nil
+}
+
+// BySymbol returns the instrument trading, or previously traded, under symbol.
+func (m *SymbolMaster) BySymbol(symbol string) (*SymbolRecord, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.bySymbol[symbol]
+	return r, ok
+}
+
+// ByCusip returns the instrument with a CUSIP.
+func (m *SymbolMaster) ByCusip(cusip string) (*SymbolRecord, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.records[cusip]
+	return r, ok
+}
+
+// Changes returns the symbol changes recorded so far, oldest first.
+func (m *SymbolMaster) Changes() []SymbolChange {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]SymbolChange(nil), m.changes...)
+}
+
+// LookupSymbol returns the instrument for symbol, asking the instruments API when it is not known yet.
+func (m *SymbolMaster) LookupSymbol(ctx context.Context, symbol string) (*SymbolRecord, error) {
+	if r, ok := m.BySymbol(symbol); ok {
+		return r, nil
+	}
+	if _, err := m.Populate(ctx, symbol, ProjectionSymbolSearch); err != nil {
+		return nil, err
+	}
+	if r, ok := m.BySymbol(symbol); ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("unknown symbol %s", symbol)
+}
+
+// LookupCusip returns the instrument for a CUSIP, asking the instruments API when it is not known yet.
+func (m *SymbolMaster) LookupCusip(ctx context.Context, cusip string) (*SymbolRecord, error) {
+	if r, ok := m.ByCusip(cusip); ok {
+		return r, nil
+	}
+	instruments, _, err := m.service.GetInstrument(ctx, cusip)
+	if err != nil {
+		return nil, err
+	}
+	m.addAll(*instruments)
+	if r, ok := m.ByCusip(cusip); ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("unknown cusip %s", cusip)
+}
+
+// Populate adds every instrument returned by SearchInstruments for symbol and projection,
+// such as a desc-regex over a family of names, and returns how many were returned.
+func (m *SymbolMaster) Populate(ctx context.Context, symbol string, projection Projection) (int, error) {
+	instruments, _, err := m.service.SearchInstruments(ctx, symbol, projection)
+	if err != nil {
+		return 0, err
+	}
+	m.addAll(*instruments)
+	return len(*instruments), nil
+}
+
+func (m *SymbolMaster) addAll(instruments Instruments) {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, info := range instruments {
+		m.add(info, now)
+	}
+}
+
+// Refresh looks up every listed instrument with a CUSIP again, recording renames and
+// marking instruments the API no longer knows as delisted. A failed lookup does not stop the
+// others; the failures are reported together once every instrument has been tried.
+func (m *SymbolMaster) Refresh(ctx context.Context) error {
+	m.mu.RLock()
+	var cusips []string
+	for _, r := range m.records {
+		if r.Cusip != "" && !r.IsDelisted() {
+			cusips = append(cusips, r.Cusip)
+		}
+	}
+	m.mu.RUnlock()
+	sort.Strings(cusips)
+
+	var failed []string
+	err := fanOut(ctx, len(cusips), requestWorkers, requestInterval, func(ctx context.Context, i int) error {
+		cusip := cusips[i]
+		instruments, _, err := m.service.GetInstrument(ctx, cusip)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failed = append(failed, fmt.Sprintf("%s: %v", cusip, err))
+			return nil
+		}
+		now := time.Now()
+		found := false
+		for _, info := range *instruments {
+			if info.Cusip == cusip {
+				found = true
+			}
+			m.add(info, now)
+		}
+		if !found {
+			m.records[cusip].Delisted = now
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("refreshing %d of %d instruments failed: %s", len(failed), len(cusips), strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// ImportCSV adds the instruments in a CSV file with a header row naming the columns cusip, symbol,
+// description, assetType and exchange, as written for InstrumentInfo. Unknown columns are ignored.
+// It returns the number of rows imported.
+func (m *SymbolMaster) ImportCSV(r io.Reader) (int, error) {
+	d, err := newCSVDecoder(r, reflect.TypeOf(InstrumentInfo{}))
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for {
+		var info InstrumentInfo
+		err := d.decode(&info)
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if info.Symbol == "" {
+			return n, fmt.Errorf("row %d has no symbol", n+1)
+		}
+		m.add(&info, now)
+		n++
+	}
+}
+
+// Search returns up to limit instruments whose description or symbol best match query,
+// tolerating misspellings and partial words. Better matches come first.
+func (m *SymbolMaster) Search(query string, limit int) []*SymbolRecord {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	type match struct {
+		record *SymbolRecord
+		score  float64
+	}
+	var matches []match
+	m.mu.RLock()
+	for _, r := range m.sortedRecords() {
+		if score := searchScore(terms, r); score > 0 {
+			matches = append(matches, match{r, score})
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	records := make([]*SymbolRecord, len(matches))
+	for i, match := range matches {
+		records[i] = match.record
+	}
+	return records
+}
+
+// searchScore averages how well each term matches the best word of a record's description,
+// counting an exact symbol match as a perfect score for its term.
+func searchScore(terms []string, r *SymbolRecord) float64 {
+	words := strings.FieldsFunc(strings.ToLower(r.Description), func(c rune) bool {
+		return !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '&')
+	})
+	symbol := strings.ToLower(r.Symbol)
+
+	total := 0.0
+	for _, term := range terms {
+		best := 0.0
+		if term == symbol {
+			best = 1
+		}
+		for _, w := range words {
+			var score float64
+			switch {
+			case w == term:
+				score = 1
+			case strings.HasPrefix(w, term):
+				score = 0.8
+			case strings.Contains(w, term):
+				score = 0.6
+			default:
+				// allow roughly one typo in every four letters
+				if sim := similarity(term, w); sim >= 0.75 {
+					score = sim * 0.7
+				}
+			}
+			if score > best {
+				best = score
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total / float64(len(terms))
+}
+
+// similarity is one minus the edit distance of a and b relative to the longer of the two.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (m *SymbolMaster) sortedRecords() []*SymbolRecord {
+	records := make([]*SymbolRecord, 0, len(m.records))
+	for _, r := range m.records {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Symbol < records[j].Symbol
+	})
+	return records
+}
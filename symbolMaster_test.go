@@ -0,0 +1,131 @@
+package tdameritrade
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSymbolMaster(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// Facebook has been renamed and Apple has disappeared.
+		list := []*InstrumentInfo{}
+		if r.URL.Path == "/instruments/30303M102" {
+			list = append(list, &InstrumentInfo{Cusip: "30303M102", Symbol: "META", Description: "Meta Platforms, Inc. - Class A Common Stock", Type: "EQUITY"})
+		}
+		json.NewEncoder(w).Encode(list)
+	})
+
+	path := filepath.Join(t.TempDir(), "symbols.json")
+	m, err := OpenSymbolMaster(c.Instrument, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := m.ImportCSV(strings.NewReader("cusip,symbol,description,assetType,exchange\n" +
+		"037833100,AAPL,Apple Inc. - Common Stock,EQUITY,NASDAQ\n" +
+		"30303M102,FB,\"Facebook, Inc. - Class A Common Stock\",EQUITY,NASDAQ\n" +
+		"594918104,MSFT,Microsoft Corporation - Common Stock,EQUITY,NASDAQ\n"))
+	if err != nil || n != 3 {
+		t.Fatalf("expected 3 rows imported, got %d %v", n, err)
+	}
+
+	if r, ok := m.ByCusip("037833100"); !ok || r.Symbol != "AAPL" {
+		t.Errorf("expected AAPL by CUSIP, got %+v", r)
+	}
+	if results := m.Search("microsoft", 1); len(results) != 1 || results[0].Symbol != "MSFT" {
+		t.Errorf("expected MSFT for an exact word, got %+v", results)
+	}
+	if results := m.Search("aple", 5); len(results) != 1 || results[0].Symbol != "AAPL" {
+		t.Errorf("expected AAPL for a misspelling, got %+v", results)
+	}
+
+	if err := m.MarkDelisted("MSFT", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := m.BySymbol("FB"); !ok || r.Symbol != "META" {
+		t.Errorf("expected the old symbol to resolve to the renamed instrument, got %+v", r)
+	}
+	if changes := m.Changes(); len(changes) != 1 || changes[0].OldSymbol != "FB" || changes[0].NewSymbol != "META" {
+		t.Errorf("expected a rename from FB to META, got %+v", changes)
+	}
+
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := OpenSymbolMaster(c.Instrument, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := loaded.BySymbol("FB"); !ok || r.Cusip != "30303M102" {
+		t.Errorf("expected renames to survive a reload, got %+v", r)
+	}
+	for _, symbol := range []string{"AAPL", "MSFT"} {
+		if r, _ := loaded.BySymbol(symbol); r == nil || !r.IsDelisted() {
+			t.Errorf("expected %s to be delisted, got %+v", symbol, r)
+		}
+	}
+	if _, err := loaded.LookupCusip(context.Background(), "000000000"); err == nil {
+		t.Error("expected an unknown CUSIP to be reported")
+	}
+}
+
+func TestSymbolMasterMergesCusiplessRecords(t *testing.T) {
+	m, err := OpenSymbolMaster(nil, filepath.Join(t.TempDir(), "symbols.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := time.Date(2021, 1, 4, 0, 0, 0, 0, MarketLocation)
+	m.Add(&InstrumentInfo{Symbol: "XYZ", Description: "XYZ Corp"}, first)
+	m.Add(&InstrumentInfo{Cusip: "123456789", Symbol: "XYZ", Exchange: "NYSE"}, first.AddDate(0, 0, 1))
+	m.Add(&InstrumentInfo{Symbol: "XYZ", Type: "EQUITY"}, first.AddDate(0, 0, 2))
+
+	if records := m.sortedRecords(); len(records) != 1 {
+		t.Fatalf("expected a single record, got %d", len(records))
+	}
+	r, ok := m.ByCusip("123456789")
+	if !ok || r.Description != "XYZ Corp" || r.Exchange != "NYSE" || r.AssetType != "EQUITY" || !r.FirstSeen.Equal(first) {
+		t.Errorf("expected the records to be merged under the CUSIP, got %+v", r)
+	}
+	if bySymbol, _ := m.BySymbol("XYZ"); bySymbol != r {
+		t.Errorf("expected the symbol to resolve to the merged record, got %+v", bySymbol)
+	}
+}
+
+func TestSymbolMasterRefreshContinuesAfterErrors(t *testing.T) {
+	interval := requestInterval
+	requestInterval = time.Millisecond
+	defer func() { requestInterval = interval }()
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/instruments/037833100" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode([]*InstrumentInfo{})
+	})
+	m, err := OpenSymbolMaster(c.Instrument, filepath.Join(t.TempDir(), "symbols.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	m.Add(&InstrumentInfo{Cusip: "037833100", Symbol: "AAPL"}, now)
+	m.Add(&InstrumentInfo{Cusip: "594918104", Symbol: "MSFT"}, now)
+
+	err = m.Refresh(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "037833100") {
+		t.Errorf("expected the failed CUSIP to be reported, got %v", err)
+	}
+	if r, _ := m.BySymbol("MSFT"); !r.IsDelisted() {
+		t.Error("expected the lookups after a failure to still run")
+	}
+	if r, _ := m.BySymbol("AAPL"); r.IsDelisted() {
+		t.Error("expected a failed lookup not to delist the instrument")
+	}
+}